@@ -0,0 +1,186 @@
+package internal
+
+import (
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-abstractions-go/store"
+)
+
+type BackedTestEntity struct {
+	backingStore store.BackingStore
+}
+
+type BackedTestEntityable interface {
+	absser.Parsable
+	store.BackedModel
+	GetId() *string
+	SetId(value *string)
+	GetDisplayName() *string
+	SetDisplayName(value *string)
+	GetManager() BackedTestEntityable
+	SetManager(value BackedTestEntityable)
+	GetMembers() []BackedTestEntityable
+	SetMembers(value []BackedTestEntityable)
+}
+
+func NewBackedTestEntity() *BackedTestEntity {
+	return &BackedTestEntity{
+		backingStore: store.BackingStoreFactoryInstance(),
+	}
+}
+
+func CreateBackedTestEntityFromDiscriminator(parseNode absser.ParseNode) (absser.Parsable, error) {
+	return NewBackedTestEntity(), nil
+}
+
+func (m *BackedTestEntity) GetBackingStore() store.BackingStore {
+	return m.backingStore
+}
+func (m *BackedTestEntity) GetId() *string {
+	val, err := m.GetBackingStore().Get("id")
+	if err != nil {
+		panic(err)
+	}
+	if val != nil {
+		return val.(*string)
+	}
+	return nil
+}
+func (m *BackedTestEntity) SetId(value *string) {
+	err := m.GetBackingStore().Set("id", value)
+	if err != nil {
+		panic(err)
+	}
+}
+func (m *BackedTestEntity) GetDisplayName() *string {
+	val, err := m.GetBackingStore().Get("displayName")
+	if err != nil {
+		panic(err)
+	}
+	if val != nil {
+		return val.(*string)
+	}
+	return nil
+}
+func (m *BackedTestEntity) SetDisplayName(value *string) {
+	err := m.GetBackingStore().Set("displayName", value)
+	if err != nil {
+		panic(err)
+	}
+}
+func (m *BackedTestEntity) GetManager() BackedTestEntityable {
+	val, err := m.GetBackingStore().Get("manager")
+	if err != nil {
+		panic(err)
+	}
+	if val != nil {
+		return val.(BackedTestEntityable)
+	}
+	return nil
+}
+func (m *BackedTestEntity) SetManager(value BackedTestEntityable) {
+	err := m.GetBackingStore().Set("manager", value)
+	if err != nil {
+		panic(err)
+	}
+}
+func (m *BackedTestEntity) GetMembers() []BackedTestEntityable {
+	val, err := m.GetBackingStore().Get("members")
+	if err != nil {
+		panic(err)
+	}
+	if val != nil {
+		return val.([]BackedTestEntityable)
+	}
+	return nil
+}
+func (m *BackedTestEntity) SetMembers(value []BackedTestEntityable) {
+	err := m.GetBackingStore().Set("members", value)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (m *BackedTestEntity) GetFieldDeserializers() map[string]func(absser.ParseNode) error {
+	res := make(map[string]func(absser.ParseNode) error)
+	res["id"] = func(n absser.ParseNode) error {
+		val, err := n.GetStringValue()
+		if err != nil {
+			return err
+		}
+		if val != nil {
+			m.SetId(val)
+		}
+		return nil
+	}
+	res["displayName"] = func(n absser.ParseNode) error {
+		val, err := n.GetStringValue()
+		if err != nil {
+			return err
+		}
+		if val != nil {
+			m.SetDisplayName(val)
+		}
+		return nil
+	}
+	res["manager"] = func(n absser.ParseNode) error {
+		val, err := n.GetObjectValue(CreateBackedTestEntityFromDiscriminator)
+		if err != nil {
+			return err
+		}
+		if val != nil {
+			m.SetManager(val.(BackedTestEntityable))
+		}
+		return nil
+	}
+	res["members"] = func(n absser.ParseNode) error {
+		val, err := n.GetCollectionOfObjectValues(CreateBackedTestEntityFromDiscriminator)
+		if err != nil {
+			return err
+		}
+		if val != nil {
+			res := make([]BackedTestEntityable, len(val))
+			for i, v := range val {
+				if v != nil {
+					res[i] = v.(BackedTestEntityable)
+				}
+			}
+			m.SetMembers(res)
+		}
+		return nil
+	}
+	return res
+}
+
+func (m *BackedTestEntity) Serialize(writer absser.SerializationWriter) error {
+	{
+		err := writer.WriteStringValue("id", m.GetId())
+		if err != nil {
+			return err
+		}
+	}
+	{
+		err := writer.WriteStringValue("displayName", m.GetDisplayName())
+		if err != nil {
+			return err
+		}
+	}
+	if m.GetManager() != nil {
+		err := writer.WriteObjectValue("manager", m.GetManager())
+		if err != nil {
+			return err
+		}
+	}
+	if m.GetMembers() != nil {
+		cast := make([]absser.Parsable, len(m.GetMembers()))
+		for i, v := range m.GetMembers() {
+			if v != nil {
+				cast[i] = v.(absser.Parsable)
+			}
+		}
+		err := writer.WriteCollectionOfObjectValues("members", cast)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
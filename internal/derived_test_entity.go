@@ -0,0 +1,60 @@
+package internal
+
+import absser "github.com/microsoft/kiota-abstractions-go/serialization"
+
+type DerivedTestEntity struct {
+	TestEntity
+	department *string
+}
+
+type DerivedTestEntityable interface {
+	TestEntityable
+	GetDepartment() *string
+	SetDepartment(value *string)
+}
+
+func NewDerivedTestEntity() *DerivedTestEntity {
+	return &DerivedTestEntity{
+		TestEntity: *NewTestEntity(),
+	}
+}
+
+func CreateDerivedTestEntityFromDiscriminator(parseNode absser.ParseNode) (absser.Parsable, error) {
+	return NewDerivedTestEntity(), nil
+}
+
+func (e *DerivedTestEntity) GetDepartment() *string {
+	return e.department
+}
+func (e *DerivedTestEntity) SetDepartment(value *string) {
+	e.department = value
+}
+
+func (e *DerivedTestEntity) GetFieldDeserializers() map[string]func(absser.ParseNode) error {
+	res := e.TestEntity.GetFieldDeserializers()
+	res["department"] = func(n absser.ParseNode) error {
+		val, err := n.GetStringValue()
+		if err != nil {
+			return err
+		}
+		if val != nil {
+			e.SetDepartment(val)
+		}
+		return nil
+	}
+	return res
+}
+
+func (m *DerivedTestEntity) Serialize(writer absser.SerializationWriter) error {
+	err := m.TestEntity.Serialize(writer)
+	if err != nil {
+		return err
+	}
+	{
+		err = writer.WriteStringValue("department", m.GetDepartment())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"math"
+	"strings"
+)
+
+type TestPermissions int
+
+const (
+	READ_TESTPERMISSIONS   TestPermissions = 1
+	WRITE_TESTPERMISSIONS  TestPermissions = 2
+	DELETE_TESTPERMISSIONS TestPermissions = 4
+	SHARE_TESTPERMISSIONS  TestPermissions = 8
+)
+
+func (i TestPermissions) String() string {
+	var values []string
+	options := []string{"read", "write", "delete", "share"}
+	for p := 0; p < 4; p++ {
+		mantis := TestPermissions(int(math.Pow(2, float64(p))))
+		if i&mantis == mantis {
+			values = append(values, options[p])
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// ParseTestPermissions only recognizes single members, leaving combinations to the parse node.
+func ParseTestPermissions(v string) (any, error) {
+	var result TestPermissions
+	switch v {
+	case "read":
+		result = READ_TESTPERMISSIONS
+	case "write":
+		result = WRITE_TESTPERMISSIONS
+	case "delete":
+		result = DELETE_TESTPERMISSIONS
+	case "share":
+		result = SHARE_TESTPERMISSIONS
+	default:
+		return nil, nil
+	}
+	return &result, nil
+}
+func SerializeTestPermissions(values []TestPermissions) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = v.String()
+	}
+	return result
+}
+func (i TestPermissions) isMultiValue() bool {
+	return true
+}
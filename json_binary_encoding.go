@@ -0,0 +1,125 @@
+package jsonserialization
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// BinaryEncoding is how byte arrays are represented in payloads.
+type BinaryEncoding int
+
+const (
+	// BinaryEncodingBase64 is padded base64 with the standard alphabet, the default.
+	BinaryEncodingBase64 BinaryEncoding = iota
+	// BinaryEncodingBase64URL is padded base64 with the URL and file name safe alphabet.
+	BinaryEncodingBase64URL
+	// BinaryEncodingRawBase64 is unpadded base64 with the standard alphabet.
+	BinaryEncodingRawBase64
+	// BinaryEncodingRawBase64URL is unpadded base64 with the URL and file name safe alphabet.
+	BinaryEncodingRawBase64URL
+	// BinaryEncodingHex is lowercase hexadecimal, any case being read.
+	BinaryEncodingHex
+	// BinaryEncodingNumberArray is an array of numbers from 0 to 255.
+	BinaryEncodingNumberArray
+)
+
+// base64Encodings are the alphabets tried in order by lenient decoding.
+var base64Encodings = []BinaryEncoding{BinaryEncodingBase64, BinaryEncodingRawBase64, BinaryEncodingBase64URL, BinaryEncodingRawBase64URL}
+
+type binaryEncodingOption BinaryEncoding
+
+func (o binaryEncodingOption) applyToParseNode(options *parseNodeOptions) {
+	options.binaryEncoding = BinaryEncoding(o)
+}
+
+func (o binaryEncodingOption) applyToSerializationWriter(options *serializationWriterOptions) {
+	options.binaryEncoding = BinaryEncoding(o)
+}
+
+// WithBinaryEncoding sets how GetByteArrayValue reads byte arrays and how WriteByteArrayValue writes them.
+// Byte slices of additional data are written the same way.
+func WithBinaryEncoding(encoding BinaryEncoding) JsonOption {
+	return binaryEncodingOption(encoding)
+}
+
+// WithLenientBinaryDecoding makes GetByteArrayValue try the other base64 alphabets, with and without
+// padding, and arrays of numbers when a value does not match the encoding set with WithBinaryEncoding.
+func WithLenientBinaryDecoding() JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.lenientBinaryDecoding = true
+	})
+}
+
+// encodeBinary returns the text of a byte array for the string encodings.
+func encodeBinary(value []byte, encoding BinaryEncoding) string {
+	switch encoding {
+	case BinaryEncodingBase64URL:
+		return base64.URLEncoding.EncodeToString(value)
+	case BinaryEncodingRawBase64:
+		return base64.RawStdEncoding.EncodeToString(value)
+	case BinaryEncodingRawBase64URL:
+		return base64.RawURLEncoding.EncodeToString(value)
+	case BinaryEncodingHex:
+		return hex.EncodeToString(value)
+	default:
+		return base64.StdEncoding.EncodeToString(value)
+	}
+}
+
+// decodeBinaryString decodes the text of a byte array for the string encodings.
+func decodeBinaryString(text string, encoding BinaryEncoding) ([]byte, error) {
+	switch encoding {
+	case BinaryEncodingBase64URL:
+		return base64.URLEncoding.DecodeString(text)
+	case BinaryEncodingRawBase64:
+		return base64.RawStdEncoding.DecodeString(text)
+	case BinaryEncodingRawBase64URL:
+		return base64.RawURLEncoding.DecodeString(text)
+	case BinaryEncodingHex:
+		return hex.DecodeString(text)
+	default:
+		return base64.StdEncoding.DecodeString(text)
+	}
+}
+
+// decodeBinary reads a byte array from the value of a node according to the options.
+func decodeBinary(value interface{}, options *parseNodeOptions) ([]byte, error) {
+	encoding := options.binaryEncoding
+	switch v := value.(type) {
+	case *string:
+		if encoding != BinaryEncodingNumberArray {
+			decoded, err := decodeBinaryString(*v, encoding)
+			if err == nil || !options.lenientBinaryDecoding {
+				return decoded, err
+			}
+		} else if !options.lenientBinaryDecoding {
+			return nil, errors.New("value is a string, an array of numbers is expected")
+		}
+		for _, alternative := range base64Encodings {
+			if decoded, err := decodeBinaryString(*v, alternative); err == nil {
+				return decoded, nil
+			}
+		}
+		return nil, fmt.Errorf("value %q is not valid base64 in any alphabet", *v)
+	case []interface{}:
+		if encoding != BinaryEncodingNumberArray && !options.lenientBinaryDecoding {
+			return nil, errors.New("value is an array, a string is expected")
+		}
+		result := make([]byte, len(v))
+		for i, element := range v {
+			if _, ok := element.(*string); ok || element == nil {
+				return nil, fmt.Errorf("element %d is not a number", i)
+			}
+			var b byte
+			if err := as(element, &b); err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			result[i] = b
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("value '%v' is not compatible with type []byte", value)
+	}
+}
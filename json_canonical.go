@@ -0,0 +1,240 @@
+package jsonserialization
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// WithCanonicalJson makes GetSerializedContent return the RFC 8785 JSON Canonicalization Scheme form of
+// the content, so the same values always give the same bytes to sign or hash: members are sorted by the
+// UTF-16 code units of their names, numbers are written as ECMAScript writes doubles, strings only escape
+// what JSON requires and no whitespace is written. The content must be a single JSON value, and its
+// numbers must fit a double. Indentation is ignored.
+func WithCanonicalJson() JsonSerializationWriterOption {
+	return serializationWriterOptionFunc(func(options *serializationWriterOptions) {
+		options.canonical = true
+	})
+}
+
+// MarshalCanonical returns the RFC 8785 canonical JSON of a Parsable value, see WithCanonicalJson.
+func MarshalCanonical(v absser.Parsable) ([]byte, error) {
+	if isNil(v) {
+		return []byte("null"), nil
+	}
+	writer := NewJsonSerializationWriter(WithCanonicalJson())
+	defer writer.Close()
+	if err := writer.WriteObjectValue("", v); err != nil {
+		return nil, err
+	}
+	return writer.GetSerializedContent()
+}
+
+// canonicalizeJson returns the RFC 8785 canonical form of a JSON document.
+func canonicalizeJson(content []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, errors.New("canonical JSON requires a value")
+	}
+	// RFC 8785 requires I-JSON: the last of duplicate names must not silently win and strings must be
+	// valid Unicode
+	if err := validateUnicodeStrings(content); err != nil {
+		return nil, fmt.Errorf("canonical JSON requires valid Unicode strings: %w", err)
+	}
+	parseNode, err := NewJsonParseNode(content, WithDuplicateKeyPolicy(DuplicateKeyError))
+	if err != nil {
+		return nil, fmt.Errorf("canonical JSON requires a single JSON value: %w", err)
+	}
+	var buffer bytes.Buffer
+	buffer.Grow(len(content))
+	if parseNode == nil {
+		buffer.WriteString("null")
+		return buffer.Bytes(), nil
+	}
+	if err := writeCanonicalValue(&buffer, parseNode); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// validateUnicodeStrings checks that the content is valid UTF-8 and that the \u escape sequences of its
+// strings do not hold lone surrogates, which the parser would replace with U+FFFD.
+func validateUnicodeStrings(content []byte) error {
+	if !utf8.Valid(content) {
+		return errors.New("the content is not valid UTF-8")
+	}
+	pendingHighSurrogate := false
+	for i := 0; i < len(content); i++ {
+		if content[i] != '\\' {
+			if pendingHighSurrogate {
+				return fmt.Errorf("lone surrogate at offset %d", i)
+			}
+			continue
+		}
+		i++
+		if i >= len(content) || content[i] != 'u' {
+			if pendingHighSurrogate {
+				return fmt.Errorf("lone surrogate at offset %d", i-1)
+			}
+			continue
+		}
+		if i+5 > len(content) {
+			return fmt.Errorf("truncated escape sequence at offset %d", i-1)
+		}
+		unit, err := strconv.ParseUint(string(content[i+1:i+5]), 16, 16)
+		if err != nil {
+			return fmt.Errorf("invalid escape sequence at offset %d", i-1)
+		}
+		switch {
+		case utf16.IsSurrogate(rune(unit)) && unit < 0xdc00:
+			if pendingHighSurrogate {
+				return fmt.Errorf("lone surrogate at offset %d", i-1)
+			}
+			pendingHighSurrogate = true
+		case utf16.IsSurrogate(rune(unit)):
+			if !pendingHighSurrogate {
+				return fmt.Errorf("lone surrogate at offset %d", i-1)
+			}
+			pendingHighSurrogate = false
+		case pendingHighSurrogate:
+			return fmt.Errorf("lone surrogate at offset %d", i-1)
+		}
+		i += 4
+	}
+	if pendingHighSurrogate {
+		return errors.New("lone surrogate at the end of the content")
+	}
+	return nil
+}
+
+// writeCanonicalValue writes the canonical form of a raw value of the tree.
+func writeCanonicalValue(buffer *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteString("null")
+	case *JsonParseNode:
+		if isNil(v) {
+			buffer.WriteString("null")
+			return nil
+		}
+		return writeCanonicalValue(buffer, v.value)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUtf16(keys[i], keys[j])
+		})
+		buffer.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			writeCanonicalString(buffer, key)
+			buffer.WriteByte(':')
+			if err := writeCanonicalValue(buffer, v[key]); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+	case []interface{}:
+		buffer.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeCanonicalValue(buffer, element); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+	case *string:
+		writeCanonicalString(buffer, *v)
+	case *bool:
+		buffer.WriteString(strconv.FormatBool(*v))
+	case *json.Number:
+		number, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return fmt.Errorf("the number %s cannot be represented as a double in canonical JSON", v)
+		}
+		text, err := formatCanonicalNumber(number)
+		if err != nil {
+			return err
+		}
+		buffer.WriteString(text)
+	default:
+		return fmt.Errorf("unexpected value of type %T", value)
+	}
+	return nil
+}
+
+// lessUtf16 compares two strings by their UTF-16 code units, as RFC 8785 sorts member names.
+func lessUtf16(left, right string) bool {
+	leftUnits, rightUnits := utf16.Encode([]rune(left)), utf16.Encode([]rune(right))
+	for i := 0; i < len(leftUnits) && i < len(rightUnits); i++ {
+		if leftUnits[i] != rightUnits[i] {
+			return leftUnits[i] < rightUnits[i]
+		}
+	}
+	return len(leftUnits) < len(rightUnits)
+}
+
+// writeCanonicalString writes a string escaping only the quotation mark, the reverse solidus and the
+// control characters, the ones that have one using their short escape sequence.
+func writeCanonicalString(buffer *bytes.Buffer, value string) {
+	buffer.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			buffer.WriteString(`\"`)
+		case '\\':
+			buffer.WriteString(`\\`)
+		case '\b':
+			buffer.WriteString(`\b`)
+		case '\f':
+			buffer.WriteString(`\f`)
+		case '\n':
+			buffer.WriteString(`\n`)
+		case '\r':
+			buffer.WriteString(`\r`)
+		case '\t':
+			buffer.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buffer, `\u%04x`, r)
+			} else {
+				buffer.WriteRune(r)
+			}
+		}
+	}
+	buffer.WriteByte('"')
+}
+
+// formatCanonicalNumber returns the text ECMAScript gives a double: the shortest decimal that reads back to
+// it, in exponent form below 1e-6 and from 1e21 on.
+func formatCanonicalNumber(value float64) (string, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", fmt.Errorf("%v cannot be written to canonical JSON", value)
+	}
+	if value == 0 {
+		// negative zero included
+		return "0", nil
+	}
+	if magnitude := math.Abs(value); magnitude >= 1e-6 && magnitude < 1e21 {
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	}
+	text := strconv.FormatFloat(value, 'e', -1, 64)
+	// ECMAScript writes the exponent without leading zeros, "1e-7" rather than "1e-07"
+	exponent := strings.IndexByte(text, 'e') + 2
+	digits := strings.TrimLeft(text[exponent:], "0")
+	return text[:exponent] + digits, nil
+}
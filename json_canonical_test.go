@@ -0,0 +1,173 @@
+package jsonserialization
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCanonicalJson(t *testing.T) {
+	// the example of section 3.2.2 of RFC 8785
+	source := `{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`
+	node, err := parseUntypedJson([]byte(source))
+	require.NoError(t, err)
+
+	serializer := NewJsonSerializationWriter(WithCanonicalJson(), WithIndent("", "  "))
+	require.NoError(t, serializer.WriteObjectValue("", node))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`, string(result))
+}
+
+func TestWriteCanonicalJsonSortsByUtf16CodeUnits(t *testing.T) {
+	// the names of the example of section 3.2.3 of RFC 8785, and characters that need no escaping
+	serializer := NewJsonSerializationWriter(WithCanonicalJson())
+	require.NoError(t, serializer.WriteObjectValue("", absser.NewUntypedObject(map[string]absser.UntypedNodeable{
+		"\u20ac":          absser.NewUntypedString("Euro Sign"),
+		"\ufb33":          absser.NewUntypedString("Hebrew Letter Dalet With Dagesh"),
+		"1":               absser.NewUntypedString("One"),
+		"\U0001F600":      absser.NewUntypedString("Emoji: Grinning Face"),
+		"\u0080":          absser.NewUntypedString("Control"),
+		"\u00f6":          absser.NewUntypedString("Latin Small Letter O With Diaeresis"),
+		"\u007f<>&\u2028": absser.NewUntypedBoolean(true),
+	})))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, "{\"1\":\"One\",\"\u007f<>&\u2028\":true,\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001F600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}", string(result))
+}
+
+func TestFormatCanonicalNumber(t *testing.T) {
+	// the examples of appendix B of RFC 8785
+	tests := []struct {
+		Bits     uint64
+		Expected string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+	for _, test := range tests {
+		t.Run(test.Expected, func(t *testing.T) {
+			result, err := formatCanonicalNumber(math.Float64frombits(test.Bits))
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, result)
+		})
+	}
+
+	_, err := formatCanonicalNumber(math.NaN())
+	assert.Error(t, err)
+	_, err = formatCanonicalNumber(math.Inf(1))
+	assert.Error(t, err)
+}
+
+func TestWriteCanonicalJsonErrors(t *testing.T) {
+	serializer := NewJsonSerializationWriter(WithCanonicalJson())
+	value := "1"
+	require.NoError(t, serializer.WriteStringValue("a", &value))
+	require.NoError(t, serializer.WriteStringValue("b", &value))
+	_, err := serializer.GetSerializedContent()
+	assert.Error(t, err)
+
+	serializer = NewJsonSerializationWriter(WithCanonicalJson())
+	require.NoError(t, serializer.WriteObjectValue("", absser.NewUntypedNode(json.Number("1e400"))))
+	_, err = serializer.GetSerializedContent()
+	assert.Error(t, err)
+
+	serializer = NewJsonSerializationWriter(WithCanonicalJson())
+	_, err = serializer.GetSerializedContent()
+	assert.Error(t, err)
+}
+
+func TestCanonicalizeJsonRequiresIJson(t *testing.T) {
+	tests := []struct {
+		Content  string
+		Expected string
+	}{
+		{`{"a":1,"a":2}`, ""},
+		{`{"a":{"b":1,"b":1}}`, ""},
+		{`"\ud800"`, ""},
+		{`"\udc00"`, ""},
+		{`"\ud800\u0041"`, ""},
+		{`"\ud800\ud800\udc00"`, ""},
+		{`"\ud800x"`, ""},
+		{`{"\ud800":1}`, ""},
+		{"\"\xff\"", ""},
+		{`"\ud83d\ude00"`, "\"\U0001F600\""},
+		{`"\\ud800"`, `"\\ud800"`},
+		{`{"a":1,"b":{"a":2}}`, `{"a":1,"b":{"a":2}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.Content, func(t *testing.T) {
+			result, err := canonicalizeJson([]byte(test.Content))
+			if test.Expected == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, string(result))
+		})
+	}
+}
+
+func TestMarshalCanonicalRejectsDuplicateNames(t *testing.T) {
+	id := "1"
+	entity := internal.NewUntypedTestEntity()
+	entity.SetId(&id)
+	entity.SetAdditionalData(map[string]interface{}{"id": "2"})
+	_, err := MarshalCanonical(entity)
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	id, office := "1", "Redmond"
+	entity := internal.NewUntypedTestEntity()
+	entity.SetId(&id)
+	entity.SetTitle(&office)
+	entity.SetAdditionalData(map[string]interface{}{
+		"zeta":  1.0,
+		"alpha": []float64{0.1, 1e21},
+		"beta":  absser.NewUntypedObject(map[string]absser.UntypedNodeable{"y": absser.NewUntypedNull(), "x": absser.NewUntypedLong(1)}),
+	})
+	first, err := MarshalCanonical(entity)
+	require.NoError(t, err)
+	assert.Equal(t, `{"alpha":[0.1,1e+21],"beta":{"x":1,"y":null},"id":"1","title":"Redmond","zeta":1}`, string(first))
+	for i := 0; i < 10; i++ {
+		again, err := MarshalCanonical(entity)
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+	}
+
+	result, err := MarshalCanonical(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(result))
+}
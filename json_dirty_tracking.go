@@ -0,0 +1,157 @@
+package jsonserialization
+
+import (
+	"reflect"
+	"sort"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-abstractions-go/store"
+)
+
+// WithDirtyTracking makes writers only write the values of backed models that changed since their backing
+// store was initialized, as PATCH requests expect. Nested backed models holding changes are written with
+// their changes even when they were not replaced, and values changed to nil are written as nulls. Objects
+// in collections are written whole, since collections are replaced as a whole. Models without a backing
+// store are written whole.
+func WithDirtyTracking() JsonSerializationWriterOption {
+	return serializationWriterOptionFunc(func(options *serializationWriterOptions) {
+		options.dirtyTracking = true
+	})
+}
+
+// backingStoreOf returns the backing store of a backed model, nil for other values.
+func backingStoreOf(value interface{}) store.BackingStore {
+	backedModel, ok := value.(store.BackedModel)
+	if !ok || isNil(backedModel) {
+		return nil
+	}
+	backingStore := backedModel.GetBackingStore()
+	if isNil(backingStore) {
+		return nil
+	}
+	return backingStore
+}
+
+// tracksDirtyValues reports whether only the changed values of the item are written.
+func (w *JsonSerializationWriter) tracksDirtyValues(item absser.Parsable) bool {
+	return w.getOptions().dirtyTracking && w.wholeObjects == 0 && backingStoreOf(item) != nil
+}
+
+// startDirtyTracking makes the backing store of the item only return its changed values when they are the
+// values written, and returns the function restoring the store.
+func (w *JsonSerializationWriter) startDirtyTracking(item absser.Parsable) func() {
+	if !w.tracksDirtyValues(item) {
+		return func() {}
+	}
+	backingStore := backingStoreOf(item)
+	previous := backingStore.GetReturnOnlyChangedValues()
+	backingStore.SetReturnOnlyChangedValues(true)
+	return func() {
+		backingStore.SetReturnOnlyChangedValues(previous)
+	}
+}
+
+// writeDirtyValues writes the values of the item that changed and were not written by its Serialize method:
+// the nested backed models holding changes and the values changed to nil.
+func (w *JsonSerializationWriter) writeDirtyValues(item absser.Parsable, keys map[string]bool) error {
+	if !w.tracksDirtyValues(item) {
+		return nil
+	}
+	values := backingStoreValues(backingStoreOf(item), false)
+	nested := make([]string, 0, len(values))
+	for key, value := range values {
+		if !keys[key] && hasChanges(value, make(map[store.BackingStore]bool)) {
+			nested = append(nested, key)
+		}
+	}
+	sort.Strings(nested)
+	for _, key := range nested {
+		var err error
+		if parsable, ok := values[key].(absser.Parsable); ok {
+			err = w.WriteObjectValue(key, parsable)
+		} else {
+			err = w.WriteCollectionOfObjectValues(key, parsableElements(values[key]))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// nil slices and maps are values changed to nil too
+	nilKeys := backingStoreOf(item).EnumerateKeysForValuesChangedToNil()
+	for key, value := range backingStoreValues(backingStoreOf(item), true) {
+		if isNil(value) {
+			nilKeys = append(nilKeys, key)
+		}
+	}
+	sort.Strings(nilKeys)
+	for _, key := range nilKeys {
+		if !keys[key] {
+			if err := w.WriteNullValue(key); err != nil {
+				return err
+			}
+			keys[key] = true
+		}
+	}
+	return nil
+}
+
+// backingStoreValues returns the values of the backing store, only the changed ones when changedOnly is set.
+func backingStoreValues(backingStore store.BackingStore, changedOnly bool) map[string]interface{} {
+	previous := backingStore.GetReturnOnlyChangedValues()
+	backingStore.SetReturnOnlyChangedValues(changedOnly)
+	values := backingStore.Enumerate()
+	backingStore.SetReturnOnlyChangedValues(previous)
+	return values
+}
+
+// hasChanges reports whether the value is a backed model, or a collection of backed models, holding changes
+// in its backing store or in the backing stores of the backed models it holds.
+func hasChanges(value interface{}, visited map[store.BackingStore]bool) bool {
+	if backingStore := backingStoreOf(value); backingStore != nil {
+		if visited[backingStore] {
+			return false
+		}
+		visited[backingStore] = true
+		if len(backingStoreValues(backingStore, true)) != 0 {
+			return true
+		}
+		for _, nested := range backingStoreValues(backingStore, false) {
+			if hasChanges(nested, visited) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, element := range parsableElements(value) {
+		if hasChanges(element, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsableElements returns the elements of a slice of Parsable values, nil for other values.
+func parsableElements(value interface{}) []absser.Parsable {
+	if isNil(value) {
+		return nil
+	}
+	slice := reflect.ValueOf(value)
+	if slice.Kind() != reflect.Slice {
+		return nil
+	}
+	elements := make([]absser.Parsable, 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		element := slice.Index(i).Interface()
+		if element == nil {
+			elements = append(elements, nil)
+			continue
+		}
+		parsable, ok := element.(absser.Parsable)
+		if !ok {
+			return nil
+		}
+		elements = append(elements, parsable)
+	}
+	return elements
+}
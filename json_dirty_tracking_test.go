@@ -0,0 +1,76 @@
+package jsonserialization
+
+import (
+	"testing"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-abstractions-go/store"
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const backedEntityPayload = `{"id":"1","displayName":"Root","manager":{"id":"2","displayName":"Manager"},"members":[{"id":"3","displayName":"A"},{"id":"4","displayName":"B"}]}`
+
+// parseBackedTestEntity reads the payload the way the backing store parse node factory intends to, values
+// read before the initialization of a store completes not being changes.
+func parseBackedTestEntity(t *testing.T) *internal.BackedTestEntity {
+	parseNode, err := NewJsonParseNode([]byte(backedEntityPayload))
+	require.NoError(t, err)
+	require.NoError(t, parseNode.SetOnBeforeAssignFieldValues(func(parsable absser.Parsable) error {
+		parsable.(store.BackedModel).GetBackingStore().SetInitializationCompleted(false)
+		return nil
+	}))
+	require.NoError(t, parseNode.SetOnAfterAssignFieldValues(func(parsable absser.Parsable) error {
+		parsable.(store.BackedModel).GetBackingStore().SetInitializationCompleted(true)
+		return nil
+	}))
+	value, err := parseNode.GetObjectValue(internal.CreateBackedTestEntityFromDiscriminator)
+	require.NoError(t, err)
+	return value.(*internal.BackedTestEntity)
+}
+
+func TestDirtyTrackingWritesChangedValues(t *testing.T) {
+	entity := parseBackedTestEntity(t)
+
+	serializer := NewJsonSerializationWriter(WithDirtyTracking())
+	require.NoError(t, serializer.WriteObjectValue("", entity))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(result))
+
+	boss, renamed := "Boss", "C"
+	entity.SetDisplayName(nil)
+	entity.GetManager().SetDisplayName(&boss)
+	entity.GetMembers()[1].SetDisplayName(&renamed)
+
+	require.NoError(t, serializer.Reset())
+	require.NoError(t, serializer.WriteObjectValue("", entity))
+	result, err = serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"manager":{"displayName":"Boss"},"members":[{"id":"3","displayName":"A"},{"id":"4","displayName":"C"}],"displayName":null}`, string(result))
+	assert.False(t, entity.GetBackingStore().GetReturnOnlyChangedValues())
+}
+
+func TestDirtyTrackingWritesReplacedObjects(t *testing.T) {
+	entity := parseBackedTestEntity(t)
+	id, name := "5", "New Manager"
+	manager := internal.NewBackedTestEntity()
+	manager.SetId(&id)
+	manager.SetDisplayName(&name)
+	entity.SetManager(manager)
+	entity.SetMembers(nil)
+
+	serializer := NewJsonSerializationWriter(WithDirtyTracking())
+	require.NoError(t, serializer.WriteObjectValue("", entity))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"manager":{"id":"5","displayName":"New Manager"},"members":null}`, string(result))
+
+	// without the option every value is written
+	serializer = NewJsonSerializationWriter()
+	require.NoError(t, serializer.WriteObjectValue("", entity))
+	result, err = serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1","displayName":"Root","manager":{"id":"5","displayName":"New Manager"}}`, string(result))
+}
@@ -0,0 +1,240 @@
+package jsonserialization
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// DiscriminatorRegistry maps the values of discriminator properties, such as "@odata.type", to the factories
+// of the types they designate. Values are matched regardless of case, as generated factories do. A registry
+// is safe for concurrent use.
+type DiscriminatorRegistry struct {
+	lock sync.RWMutex
+	// properties are the discriminator property names in registration order.
+	properties []string
+	// mappings holds the mappings of each property by lower case value.
+	mappings map[string]map[string]discriminatorMapping
+	// types holds the first mapping registered for each type.
+	types map[reflect.Type]discriminatorMapping
+}
+
+// discriminatorMapping is a registered discriminator value.
+type discriminatorMapping struct {
+	property string
+	value    string
+	factory  absser.ParsableFactory
+}
+
+// NewDiscriminatorRegistry creates a new empty DiscriminatorRegistry.
+func NewDiscriminatorRegistry() *DiscriminatorRegistry {
+	return &DiscriminatorRegistry{
+		mappings: make(map[string]map[string]discriminatorMapping),
+		types:    make(map[reflect.Type]discriminatorMapping),
+	}
+}
+
+// Register maps the value of the discriminator property to the factory. The factory is called once with an
+// empty object to learn the type it creates, which is the type the writer writes the value for.
+func (r *DiscriminatorRegistry) Register(property string, value string, factory absser.ParsableFactory) error {
+	if property == "" {
+		return errors.New("property is empty")
+	}
+	if value == "" {
+		return errors.New("value is empty")
+	}
+	if factory == nil {
+		return errors.New("factory is nil")
+	}
+	prototype, err := factory(&JsonParseNode{value: map[string]interface{}{}})
+	if err != nil {
+		return fmt.Errorf("the factory of %q failed to create a value: %w", value, err)
+	}
+	mapping := discriminatorMapping{property: property, value: value, factory: factory}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	values, ok := r.mappings[property]
+	if !ok {
+		values = make(map[string]discriminatorMapping)
+		r.mappings[property] = values
+		r.properties = append(r.properties, property)
+	}
+	normalized := strings.ToLower(value)
+	if existing, ok := values[normalized]; ok {
+		return fmt.Errorf("value %q of property %q is already registered as %q", value, property, existing.value)
+	}
+	values[normalized] = mapping
+	if prototype != nil {
+		if _, ok := r.types[reflect.TypeOf(prototype)]; !ok {
+			r.types[reflect.TypeOf(prototype)] = mapping
+		}
+	}
+	return nil
+}
+
+// Lookup returns the factory the value of the discriminator property is mapped to.
+func (r *DiscriminatorRegistry) Lookup(property string, value string) (absser.ParsableFactory, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	mapping, ok := r.mappings[property][strings.ToLower(value)]
+	return mapping.factory, ok
+}
+
+// Resolve returns the factory designated by the first registered discriminator property the node holds,
+// nil when it holds none or when the value is not registered.
+func (r *DiscriminatorRegistry) Resolve(parseNode absser.ParseNode) (absser.ParsableFactory, error) {
+	if isNil(parseNode) {
+		return nil, nil
+	}
+	r.lock.RLock()
+	properties := r.properties
+	r.lock.RUnlock()
+	for _, property := range properties {
+		valueNode, err := parseNode.GetChildNode(property)
+		if err != nil {
+			return nil, err
+		}
+		if isNil(valueNode) {
+			continue
+		}
+		value, err := valueNode.GetStringValue()
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		if factory, ok := r.Lookup(property, *value); ok {
+			return factory, nil
+		}
+	}
+	return nil, nil
+}
+
+// discriminatorOf returns the discriminator property and value registered for the type of the value.
+func (r *DiscriminatorRegistry) discriminatorOf(value absser.Parsable) (discriminatorMapping, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	mapping, ok := r.types[reflect.TypeOf(value)]
+	return mapping, ok
+}
+
+type discriminatorRegistryOption struct {
+	registry *DiscriminatorRegistry
+}
+
+func (o discriminatorRegistryOption) applyToParseNode(options *parseNodeOptions) {
+	options.discriminators = o.registry
+}
+
+func (o discriminatorRegistryOption) applyToSerializationWriter(options *serializationWriterOptions) {
+	options.discriminators = o.registry
+}
+
+// WithDiscriminatorRegistry sets the registry of discriminator values. GetObjectValue and
+// GetCollectionOfObjectValues create the type designated by the discriminator of an object when it derives
+// from the type the factory they are given creates, embedding it. Writers write the discriminator of the
+// registered types after their properties when their Serialize method does not write it.
+func WithDiscriminatorRegistry(registry *DiscriminatorRegistry) JsonOption {
+	return discriminatorRegistryOption{registry: registry}
+}
+
+// resolveDerivedType returns the value of the type designated by the discriminator of the node when it
+// derives from the type of the result, and the result otherwise.
+func (n *JsonParseNode) resolveDerivedType(result absser.Parsable) (absser.Parsable, error) {
+	registry := n.getOptions().discriminators
+	if registry == nil || isNil(result) {
+		return result, nil
+	}
+	if _, ok := n.value.(map[string]interface{}); !ok {
+		return result, nil
+	}
+	factory, err := registry.Resolve(n)
+	if err != nil || factory == nil {
+		return result, err
+	}
+	derived, err := factory(n)
+	if err != nil || isNil(derived) {
+		return result, err
+	}
+	if reflect.TypeOf(derived) == reflect.TypeOf(result) || !derivesFrom(reflect.TypeOf(derived), reflect.TypeOf(result)) {
+		return result, nil
+	}
+	return derived, nil
+}
+
+// derivesFrom reports whether the struct of the derived type embeds the struct of the base type, directly
+// or through other embedded structs.
+func derivesFrom(derived reflect.Type, base reflect.Type) bool {
+	derived, base = structOf(derived), structOf(base)
+	if derived == nil || base == nil {
+		return false
+	}
+	visited := make(map[reflect.Type]bool)
+	pending := []reflect.Type{derived}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		for i := 0; i < current.NumField(); i++ {
+			field := current.Field(i)
+			if !field.Anonymous {
+				continue
+			}
+			embedded := structOf(field.Type)
+			if embedded == base {
+				return true
+			}
+			if embedded != nil {
+				pending = append(pending, embedded)
+			}
+		}
+	}
+	return false
+}
+
+// structOf returns the struct type of a struct or of a pointer to a struct, nil for other types.
+func structOf(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// writeDiscriminator writes the discriminator of a registered type when the properties of the object did
+// not include it.
+func (w *JsonSerializationWriter) writeDiscriminator(item absser.Parsable, keys map[string]bool) error {
+	registry := w.getOptions().discriminators
+	if registry == nil {
+		return nil
+	}
+	mapping, ok := registry.discriminatorOf(item)
+	if !ok || keys[mapping.property] {
+		return nil
+	}
+	w.rawPropertyName = true
+	err := w.WriteStringValue(mapping.property, &mapping.value)
+	w.rawPropertyName = false
+	return err
+}
+
+// hasDiscriminator reports whether a discriminator is registered for the type of the item.
+func (w *JsonSerializationWriter) hasDiscriminator(item absser.Parsable) bool {
+	registry := w.getOptions().discriminators
+	if registry == nil || isNil(item) {
+		return false
+	}
+	_, ok := registry.discriminatorOf(item)
+	return ok
+}
@@ -0,0 +1,109 @@
+package jsonserialization
+
+import (
+	"testing"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDiscriminatorRegistry(t *testing.T) *DiscriminatorRegistry {
+	registry := NewDiscriminatorRegistry()
+	require.NoError(t, registry.Register("@odata.type", "#microsoft.graph.testEntity", internal.CreateTestEntityFromDiscriminator))
+	require.NoError(t, registry.Register("@odata.type", "#microsoft.graph.derivedTestEntity", internal.CreateDerivedTestEntityFromDiscriminator))
+	require.NoError(t, registry.Register("@odata.type", "#microsoft.graph.secondTestEntity", internal.CreateSecondTestEntityFromDiscriminator))
+	return registry
+}
+
+func TestDiscriminatorRegistryRegister(t *testing.T) {
+	registry := newTestDiscriminatorRegistry(t)
+	assert.Error(t, registry.Register("", "value", internal.CreateTestEntityFromDiscriminator))
+	assert.Error(t, registry.Register("kind", "", internal.CreateTestEntityFromDiscriminator))
+	assert.Error(t, registry.Register("kind", "value", nil))
+	assert.Error(t, registry.Register("@odata.type", "#Microsoft.Graph.TestEntity", internal.CreateSecondTestEntityFromDiscriminator))
+
+	factory, ok := registry.Lookup("@odata.type", "#MICROSOFT.GRAPH.DERIVEDTESTENTITY")
+	require.True(t, ok)
+	value, err := factory(nil)
+	require.NoError(t, err)
+	assert.IsType(t, &internal.DerivedTestEntity{}, value)
+	_, ok = registry.Lookup("kind", "#microsoft.graph.testEntity")
+	assert.False(t, ok)
+
+	parseNode, err := NewJsonParseNode([]byte(`{"@odata.type": "#microsoft.graph.secondTestEntity"}`))
+	require.NoError(t, err)
+	factory, err = registry.Resolve(parseNode)
+	require.NoError(t, err)
+	require.NotNil(t, factory)
+	value, err = factory(parseNode)
+	require.NoError(t, err)
+	assert.IsType(t, &internal.SecondTestEntity{}, value)
+}
+
+func TestGetObjectValueResolvesDerivedTypes(t *testing.T) {
+	source := `[
+		{"@odata.type": "#microsoft.graph.derivedTestEntity", "id": "1", "department": "Sales"},
+		{"@odata.type": "#microsoft.graph.testEntity", "id": "2"},
+		{"@odata.type": "#microsoft.graph.secondTestEntity", "id": "3"},
+		{"@odata.type": "#microsoft.graph.unknown", "id": "4"},
+		{"id": "5"}
+	]`
+	parseNode, err := NewJsonParseNode([]byte(source), WithDiscriminatorRegistry(newTestDiscriminatorRegistry(t)))
+	require.NoError(t, err)
+	values, err := parseNode.GetCollectionOfObjectValues(internal.CreateTestEntityFromDiscriminator)
+	require.NoError(t, err)
+	require.Len(t, values, 5)
+
+	derived, ok := values[0].(*internal.DerivedTestEntity)
+	require.True(t, ok, "%T", values[0])
+	assert.Equal(t, "1", *derived.GetId())
+	assert.Equal(t, "Sales", *derived.GetDepartment())
+	// the second test entity does not derive from the test entity
+	for i, value := range values[1:] {
+		entity, ok := value.(*internal.TestEntity)
+		require.True(t, ok, "%T", value)
+		assert.Equal(t, string(rune('2'+i)), *entity.GetId())
+	}
+
+	parseNode, err = NewJsonParseNode([]byte(source))
+	require.NoError(t, err)
+	values, err = parseNode.GetCollectionOfObjectValues(internal.CreateTestEntityFromDiscriminator)
+	require.NoError(t, err)
+	assert.IsType(t, &internal.TestEntity{}, values[0])
+}
+
+func TestWriteObjectValueWritesDiscriminators(t *testing.T) {
+	registry := newTestDiscriminatorRegistry(t)
+	require.NoError(t, registry.Register("@odata.type", "#microsoft.graph.custom", func(absser.ParseNode) (absser.Parsable, error) {
+		return &discriminatedEntity{}, nil
+	}))
+	serializer := NewJsonSerializationWriter(WithDiscriminatorRegistry(registry))
+	id, department := "1", "Sales"
+	derived := internal.NewDerivedTestEntity()
+	derived.SetId(&id)
+	derived.SetDepartment(&department)
+	require.NoError(t, serializer.WriteCollectionOfObjectValues("value", []absser.Parsable{derived, &discriminatedEntity{}, internal.NewSecondTestEntity()}))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `"value":[`+
+		`{"id":"1","department":"Sales","@odata.type":"#microsoft.graph.derivedTestEntity"},`+
+		`{"@odata.type":"#microsoft.graph.custom","nested":{"@odata.type":"#microsoft.graph.testEntity"}},`+
+		`{"@odata.type":"#microsoft.graph.secondTestEntity"}]`, string(result))
+}
+
+// discriminatedEntity writes its own discriminator, and a nested object that writes none.
+type discriminatedEntity struct{}
+
+func (e *discriminatedEntity) Serialize(writer absser.SerializationWriter) error {
+	discriminator := "#microsoft.graph.custom"
+	if err := writer.WriteStringValue("@odata.type", &discriminator); err != nil {
+		return err
+	}
+	return writer.WriteObjectValue("nested", internal.NewTestEntity())
+}
+
+func (e *discriminatedEntity) GetFieldDeserializers() map[string]func(absser.ParseNode) error {
+	return nil
+}
@@ -0,0 +1,215 @@
+package jsonserialization
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// defaultEnumSeparator separates the members of flags enum values unless WithEnumSeparator is used.
+const defaultEnumSeparator = ","
+
+// WithEnumSeparator sets the separator of the members of flags enum values read by GetEnumValue and
+// GetCollectionOfEnumValues, "," by default. Whitespace around members is ignored. Writers always join
+// members with ",", the form the String method of generated flags enums returns.
+func WithEnumSeparator(separator string) JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.enumSeparator = separator
+	})
+}
+
+// WithCaseInsensitiveEnums makes GetEnumValue and GetCollectionOfEnumValues retry the values the enum
+// factory does not recognize in lower case and in camel case, the casing of generated enum factories.
+func WithCaseInsensitiveEnums() JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.caseInsensitiveEnums = true
+	})
+}
+
+// enumSeparatorOf returns the separator of flags enum members from the setting of the options.
+func enumSeparatorOf(separator string) string {
+	if separator == "" {
+		return defaultEnumSeparator
+	}
+	return separator
+}
+
+// parseEnum reads an enum value with the factory. Values holding several members are passed whole to the
+// factory first, as generated factories of flags enums split them, and then member by member, the
+// results being combined with a bitwise or. It returns nil when a member is not recognized or when the
+// enum is not a flags enum, and the error of the factory for the whole value when it returned one.
+func parseEnum(parser absser.EnumFactory, text string, options *parseNodeOptions) (interface{}, error) {
+	value, err := parseEnumMember(parser, text, options)
+	if err == nil && !isNil(value) {
+		return value, nil
+	}
+	separator := enumSeparatorOf(options.enumSeparator)
+	if !strings.Contains(text, separator) {
+		return nil, err
+	}
+	var members []interface{}
+	for _, member := range strings.Split(text, separator) {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		value, memberErr := parseEnumMember(parser, member, options)
+		if memberErr != nil || isNil(value) {
+			if err == nil {
+				err = memberErr
+			}
+			return nil, err
+		}
+		members = append(members, value)
+	}
+	if len(members) == 0 {
+		return nil, err
+	}
+	combined, combineErr := combineFlags(members)
+	if combineErr != nil || !isNil(combined) {
+		return combined, combineErr
+	}
+	return nil, err
+}
+
+// parseEnumMember reads a single member with the factory, retrying other casings when enums are case
+// insensitive. The error of the factory for the member as it is is returned when no casing is recognized.
+func parseEnumMember(parser absser.EnumFactory, member string, options *parseNodeOptions) (interface{}, error) {
+	value, err := parser(member)
+	if (err == nil && !isNil(value)) || !options.caseInsensitiveEnums {
+		return value, err
+	}
+	lower := strings.ToLower(member)
+	first, size := utf8.DecodeRuneInString(member)
+	for _, candidate := range []string{lower, string(unicode.ToLower(first)) + member[size:]} {
+		if candidate == member {
+			continue
+		}
+		candidateValue, candidateErr := parser(candidate)
+		if candidateErr == nil && !isNil(candidateValue) {
+			return candidateValue, nil
+		}
+	}
+	return nil, err
+}
+
+// combineFlags returns the bitwise or of the members of a flags enum, which are pointers to an integer type,
+// or nil when the members are not the flags of a flags enum.
+func combineFlags(members []interface{}) (interface{}, error) {
+	if len(members) == 1 {
+		return members[0], nil
+	}
+	first := reflect.ValueOf(members[0])
+	if first.Kind() != reflect.Pointer {
+		return nil, fmt.Errorf("enum values of type %T cannot be combined, a pointer to an integer type is expected", members[0])
+	}
+	result := reflect.New(first.Type().Elem())
+	for _, member := range members {
+		value := reflect.ValueOf(member)
+		if value.Type() != first.Type() {
+			return nil, fmt.Errorf("enum members are of different types %T and %T", members[0], member)
+		}
+		switch value.Elem().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			result.Elem().SetInt(result.Elem().Int() | value.Elem().Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			result.Elem().SetUint(result.Elem().Uint() | value.Elem().Uint())
+		default:
+			return nil, fmt.Errorf("enum values of type %T cannot be combined, a pointer to an integer type is expected", member)
+		}
+	}
+	combined := result.Interface()
+	if !isFlagsCombination(combined, members) {
+		return nil, nil
+	}
+	return combined, nil
+}
+
+// isFlagsCombination reports whether the String form of a combined value lists exactly its members, which
+// tells flags enums apart from other enums, whose members cannot be combined.
+func isFlagsCombination(combined interface{}, members []interface{}) bool {
+	combinedStringer, ok := combined.(fmt.Stringer)
+	if !ok {
+		return true
+	}
+	names := make(map[string]bool)
+	for _, name := range strings.Split(combinedStringer.String(), defaultEnumSeparator) {
+		names[strings.TrimSpace(name)] = true
+	}
+	found := make(map[string]bool, len(members))
+	for _, member := range members {
+		memberStringer, ok := member.(fmt.Stringer)
+		if !ok || !names[memberStringer.String()] {
+			return false
+		}
+		found[memberStringer.String()] = true
+	}
+	return len(found) == len(names)
+}
+
+// formatEnum returns the canonical form of the text of an enum value: its members trimmed and written
+// once each in their order, joined with ",".
+func formatEnum(text string) string {
+	if !strings.Contains(text, defaultEnumSeparator) {
+		return text
+	}
+	members := strings.Split(text, defaultEnumSeparator)
+	seen := make(map[string]bool, len(members))
+	result := members[:0]
+	for _, member := range members {
+		member = strings.TrimSpace(member)
+		if member == "" || seen[member] {
+			continue
+		}
+		seen[member] = true
+		result = append(result, member)
+	}
+	return strings.Join(result, defaultEnumSeparator)
+}
+
+// EnumWriter writes enum values in their canonical form. JsonSerializationWriter implements it. Generated
+// models write the String form of enum values with WriteStringValue, which writes the text as it is.
+type EnumWriter interface {
+	// WriteEnumValue writes the String form of an enum value.
+	WriteEnumValue(key string, value fmt.Stringer) error
+	// WriteCollectionOfEnumValues writes a collection of enum values.
+	WriteCollectionOfEnumValues(key string, collection []fmt.Stringer) error
+}
+
+// WriteEnumValue writes the String form of an enum value. The members of flags enum values are written
+// once each, in the order of the String form, joined with ",".
+func (w *JsonSerializationWriter) WriteEnumValue(key string, value fmt.Stringer) error {
+	if isNil(value) {
+		return w.err
+	}
+	text := formatEnum(value.String())
+	return w.WriteStringValue(key, &text)
+}
+
+// WriteCollectionOfEnumValues writes a collection of enum values as WriteEnumValue does, nil values as nulls.
+func (w *JsonSerializationWriter) WriteCollectionOfEnumValues(key string, collection []fmt.Stringer) error {
+	if collection != nil { // empty collections are meaningful
+		if key != "" {
+			w.writePropertyName(key)
+		}
+		w.writeArrayStart()
+		for _, item := range collection {
+			var err error
+			if isNil(item) {
+				err = w.WriteNullValue("")
+			} else {
+				err = w.WriteEnumValue("", item)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		w.writeArrayEnd()
+	}
+	return w.err
+}
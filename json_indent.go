@@ -0,0 +1,41 @@
+package jsonserialization
+
+import (
+	"strings"
+)
+
+// WithIndent makes writers write each member of an object and each element of an array on a new line,
+// starting with the prefix followed by one copy of the indent per level of nesting, as json.MarshalIndent
+// does. Property names are followed by a space and empty objects and arrays are written as {} and [].
+func WithIndent(prefix, indent string) JsonSerializationWriterOption {
+	return serializationWriterOptionFunc(func(options *serializationWriterOptions) {
+		options.indented = true
+		options.indentPrefix = prefix
+		options.indent = indent
+	})
+}
+
+// SetIndent makes the writer write indented JSON from now on, as WithIndent does. Calling it with an
+// empty prefix and indent still writes members and elements on their own lines.
+func (w *JsonSerializationWriter) SetIndent(prefix, indent string) {
+	options := *w.getOptions()
+	WithIndent(prefix, indent).applyToSerializationWriter(&options)
+	w.options = &options
+}
+
+// lineBreak returns the line break and indentation of the current level of nesting, empty when the
+// writer does not indent.
+func (w *JsonSerializationWriter) lineBreak() string {
+	options := w.getOptions()
+	if !options.indented {
+		return ""
+	}
+	return "\n" + options.indentPrefix + strings.Repeat(options.indent, len(w.containers)-1)
+}
+
+// writeLineBreak starts a new line when the writer indents.
+func (w *JsonSerializationWriter) writeLineBreak() {
+	if w.getOptions().indented {
+		w.getWriter().WriteString(w.lineBreak())
+	}
+}
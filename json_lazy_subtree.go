@@ -0,0 +1,60 @@
+package jsonserialization
+
+// lazySubtree is the value of a node whose object or array was skipped when the payload was loaded,
+// see WithLazyLoading. The node offset is the start of the subtree in content.
+type lazySubtree struct {
+	content []byte
+	// end is the offset just past the subtree.
+	end int64
+}
+
+// skipContainer moves past the object or array starting at offset and returns a node holding its range
+// of the content. The subtree is validated and checked against the depth, token and string length limits.
+func (l *treeLoader) skipContainer(offset int64) (*JsonParseNode, error) {
+	if err := l.enterContainer(offset); err != nil {
+		return nil, err
+	}
+	for depth := 1; depth > 0; {
+		token, err := l.nextToken()
+		if err != nil {
+			return nil, l.wrapError(err)
+		}
+		switch token.kind {
+		case tokenBeginObject, tokenBeginArray:
+			if err := l.enterContainer(token.offset); err != nil {
+				return nil, err
+			}
+			depth++
+		case tokenEndObject, tokenEndArray:
+			l.exitContainer()
+			depth--
+		}
+	}
+	return l.newNode(&lazySubtree{content: l.content, end: l.tokens.inputOffset()}, offset), nil
+}
+
+// materialize loads the object or array of a node that was skipped, its own nested containers being
+// skipped in turn. It does nothing for nodes that are already loaded.
+func (n *JsonParseNode) materialize() error {
+	subtree, ok := n.value.(*lazySubtree)
+	if !ok {
+		return nil
+	}
+	loader := newTreeLoader(newSubtreeScanner(subtree.content, n.offset, subtree.end, n.source), n.options)
+	defer loader.release()
+	loader.content = subtree.content
+	loader.root = n
+	token, err := loader.nextToken()
+	if err != nil {
+		return loader.wrapError(err)
+	}
+	value, err := loader.loadValue(&token)
+	if err != nil {
+		return err
+	}
+	loaded := value.(*JsonParseNode)
+	n.value = loaded.value
+	n.members = loaded.members
+	n.elementOffsets = loaded.elementOffsets
+	return nil
+}
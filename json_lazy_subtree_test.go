@@ -0,0 +1,102 @@
+package jsonserialization
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyLoadingSkipsNestedContainers(t *testing.T) {
+	source := `{"id":"1","manager":{"id":"2","reports":[{"id":"3"}]},"tags":["a","b"]}`
+	parseNode, err := NewJsonParseNode([]byte(source), WithLazyLoading())
+	require.NoError(t, err)
+
+	properties := parseNode.value.(map[string]interface{})
+	assert.IsType(t, &lazySubtree{}, properties["manager"].(*JsonParseNode).value)
+	assert.IsType(t, &lazySubtree{}, properties["tags"].(*JsonParseNode).value)
+
+	manager, err := parseNode.GetChildNode("manager")
+	require.NoError(t, err)
+	managerProperties := manager.(*JsonParseNode).value.(map[string]interface{})
+	assert.IsType(t, &lazySubtree{}, managerProperties["reports"].(*JsonParseNode).value)
+	assert.IsType(t, &lazySubtree{}, properties["tags"].(*JsonParseNode).value)
+
+	eager, err := NewJsonParseNode([]byte(source))
+	require.NoError(t, err)
+	expected, err := eager.GetRawValue()
+	require.NoError(t, err)
+	lazy, err := NewJsonParseNode([]byte(source), WithLazyLoading())
+	require.NoError(t, err)
+	actual, err := lazy.GetRawValue()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestLazyLoadingReadsObjects(t *testing.T) {
+	source := `{"id":"1","manager":{"id":"2","officeLocation":"Paris"},"createdDateTime":"2017-07-29T03:07:25Z"}`
+	parseNode, err := NewJsonParseNode([]byte(source), WithLazyLoading())
+	require.NoError(t, err)
+
+	result, err := parseNode.GetObjectValue(internal.CreateTestEntityFromDiscriminator)
+	require.NoError(t, err)
+	entity := result.(*internal.TestEntity)
+	assert.Equal(t, "1", *entity.GetId())
+	require.NotNil(t, entity.GetCreatedDateTime())
+	manager := entity.GetAdditionalData()["manager"].(map[string]interface{})
+	assert.Equal(t, "2", *manager["id"].(*string))
+	assert.Equal(t, "Paris", *manager["officeLocation"].(*string))
+}
+
+func TestLazyLoadingValidatesSkippedContainers(t *testing.T) {
+	_, err := NewJsonParseNode([]byte(`{"a":{"b":[1,2}}`), WithLazyLoading())
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, int64(14), parseErr.Offset)
+
+	_, err = NewJsonParseNode([]byte(`{"a":[[[1]]]}`), WithLazyLoading(), WithLimits(Limits{MaxDepth: 3}))
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, DepthLimit, limitErr.Limit)
+}
+
+func TestLazyLoadingLocatesErrorsInSubtrees(t *testing.T) {
+	source := "{\n  \"a\": {\n    \"b\": [1, \"x\"]\n  }\n}"
+	parseNode, err := NewJsonParseNode([]byte(source), WithLazyLoading())
+	require.NoError(t, err)
+
+	a, err := parseNode.GetChildNode("a")
+	require.NoError(t, err)
+	b, err := a.GetChildNode("b")
+	require.NoError(t, err)
+	_, err = b.GetCollectionOfPrimitiveValues("int32")
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, "/a/b/1", parseErr.Pointer)
+	assert.Equal(t, 3, parseErr.Line)
+	assert.Equal(t, 14, parseErr.Column)
+	assert.Equal(t, int64(strings.Index(source, `"x"`)), parseErr.Offset)
+}
+
+func TestLazyLoadingChecksDuplicateKeysWhenLoading(t *testing.T) {
+	source := `{"a":{"id":1,"id":2}}`
+	parseNode, err := NewJsonParseNode([]byte(source), WithLazyLoading(), WithDuplicateKeyPolicy(DuplicateKeyError))
+	require.NoError(t, err)
+
+	_, err = parseNode.GetChildNode("a")
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.True(t, errors.Is(err, ErrDuplicateKey))
+	assert.Equal(t, "/a/id", parseErr.Pointer)
+	assert.Equal(t, int64(strings.LastIndex(source, `"id"`)), parseErr.Offset)
+}
+
+func TestLazyLoadingDoesNotApplyToReaders(t *testing.T) {
+	parseNode, err := NewJsonParseNodeFromReader(strings.NewReader(`{"a":{"b":1}}`), WithLazyLoading())
+	require.NoError(t, err)
+	properties := parseNode.value.(map[string]interface{})
+	assert.IsType(t, map[string]interface{}{}, properties["a"].(*JsonParseNode).value)
+}
@@ -0,0 +1,159 @@
+package jsonserialization
+
+import (
+	"fmt"
+)
+
+// Limits bounds the resources a payload may use while it is loaded, so untrusted input is rejected
+// before it exhausts memory. A zero field means no limit.
+type Limits struct {
+	// MaxDepth is the maximum number of nested objects and arrays.
+	MaxDepth int
+	// MaxTokens is the maximum number of tokens in the payload, delimiters and keys included.
+	MaxTokens int
+	// MaxStringLength is the maximum length in bytes of a string value or key, as written in the payload.
+	MaxStringLength int
+	// MaxArrayLength is the maximum number of elements of an array.
+	MaxArrayLength int
+	// MaxObjectMembers is the maximum number of members of an object.
+	MaxObjectMembers int
+	// MaxTreeMemory is the maximum estimated memory in bytes of the loaded tree.
+	MaxTreeMemory int64
+}
+
+// WithLimits sets the limits checked while payloads are loaded. A payload exceeding a limit fails as
+// soon as the limit is reached with a *ParseError wrapping a *LimitExceededError.
+func WithLimits(limits Limits) JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.limits = limits
+	})
+}
+
+// LimitKind identifies one of the Limits.
+type LimitKind int
+
+const (
+	DepthLimit LimitKind = iota
+	TokenLimit
+	StringLengthLimit
+	ArrayLengthLimit
+	ObjectMembersLimit
+	TreeMemoryLimit
+)
+
+// String returns the name of the limit.
+func (k LimitKind) String() string {
+	switch k {
+	case DepthLimit:
+		return "depth"
+	case TokenLimit:
+		return "token"
+	case StringLengthLimit:
+		return "string length"
+	case ArrayLengthLimit:
+		return "array length"
+	case ObjectMembersLimit:
+		return "object members"
+	case TreeMemoryLimit:
+		return "tree memory"
+	default:
+		return "unknown"
+	}
+}
+
+// LimitExceededError reports a payload exceeding one of the Limits.
+type LimitExceededError struct {
+	// Limit is the limit that was exceeded.
+	Limit LimitKind
+	// Max is the value of the limit.
+	Max int64
+}
+
+// Error returns the limit that was exceeded.
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s limit of %d exceeded", e.Limit, e.Max)
+}
+
+// The estimated memory used by the values of a tree, in bytes, beyond the text they hold.
+const (
+	primitiveMemory = 24
+	memberMemory    = 64
+	elementMemory   = 16
+	containerMemory = 160
+)
+
+// loadUsage tracks the resources used by the tree being loaded.
+type loadUsage struct {
+	depth  int
+	tokens int
+	memory int64
+}
+
+// limitError returns the error reporting the limit exceeded by the value being loaded.
+func (l *treeLoader) limitError(offset int64, limit LimitKind, max int64) error {
+	return newParseErrorAt(l.pointer(), l.source(), offset, nil, "", &LimitExceededError{Limit: limit, Max: max})
+}
+
+// useToken accounts for a token read from the payload.
+func (l *treeLoader) useToken(token *jsonToken) error {
+	limits := &l.getOptions().limits
+	l.usage.tokens++
+	if limits.MaxTokens > 0 && l.usage.tokens > limits.MaxTokens {
+		return l.limitError(token.offset, TokenLimit, int64(limits.MaxTokens))
+	}
+	switch token.kind {
+	case tokenString:
+		if limits.MaxStringLength > 0 && len(token.text) > limits.MaxStringLength {
+			return l.limitError(token.offset, StringLengthLimit, int64(limits.MaxStringLength))
+		}
+		return l.useMemory(int64(len(token.text))+primitiveMemory, token.offset)
+	case tokenNumber:
+		return l.useMemory(int64(len(token.text))+primitiveMemory, token.offset)
+	case tokenTrue, tokenFalse:
+		return l.useMemory(primitiveMemory, token.offset)
+	}
+	return nil
+}
+
+// useMemory accounts for memory used by the tree.
+func (l *treeLoader) useMemory(size int64, offset int64) error {
+	max := l.getOptions().limits.MaxTreeMemory
+	l.usage.memory += size
+	if max > 0 && l.usage.memory > max {
+		return l.limitError(offset, TreeMemoryLimit, max)
+	}
+	return nil
+}
+
+// enterContainer accounts for an object or an array being loaded.
+func (l *treeLoader) enterContainer(offset int64) error {
+	max := l.getOptions().limits.MaxDepth
+	l.usage.depth++
+	if max > 0 && l.usage.depth > max {
+		return l.limitError(offset, DepthLimit, int64(max))
+	}
+	return l.useMemory(containerMemory, offset)
+}
+
+// exitContainer accounts for the end of an object or an array.
+func (l *treeLoader) exitContainer() {
+	l.usage.depth--
+}
+
+// useMember accounts for the count-th member of an object.
+func (l *treeLoader) useMember(count int, key string, offset int64) error {
+	max := l.getOptions().limits.MaxObjectMembers
+	if max > 0 && count > max {
+		return l.limitError(offset, ObjectMembersLimit, int64(max))
+	}
+	return l.useMemory(int64(len(key))+memberMemory, offset)
+}
+
+// useElement accounts for the count-th element of an array.
+func (l *treeLoader) useElement(count int, offset int64) error {
+	max := l.getOptions().limits.MaxArrayLength
+	if max > 0 && count > max {
+		return l.limitError(offset, ArrayLengthLimit, int64(max))
+	}
+	return l.useMemory(elementMemory, offset)
+}
@@ -0,0 +1,61 @@
+package jsonserialization
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits(t *testing.T) {
+	cases := []struct {
+		Title   string
+		Input   string
+		Limits  Limits
+		Limit   LimitKind
+		Pointer string
+	}{
+		{Title: "Depth", Input: `{"a": [[1]]}`, Limits: Limits{MaxDepth: 2}, Limit: DepthLimit, Pointer: "/a/0"},
+		{Title: "Tokens", Input: `[1, 2, 3, 4]`, Limits: Limits{MaxTokens: 3}, Limit: TokenLimit, Pointer: "/2"},
+		{Title: "String", Input: `{"a": "abc", "b": "abcd"}`, Limits: Limits{MaxStringLength: 3}, Limit: StringLengthLimit, Pointer: "/b"},
+		{Title: "Key", Input: `{"abcd": 1}`, Limits: Limits{MaxStringLength: 3}, Limit: StringLengthLimit, Pointer: ""},
+		{Title: "Array", Input: `{"a": [1, 2, 3]}`, Limits: Limits{MaxArrayLength: 2}, Limit: ArrayLengthLimit, Pointer: "/a/2"},
+		{Title: "Members", Input: `{"a": 1, "b": 2, "c": 3}`, Limits: Limits{MaxObjectMembers: 2}, Limit: ObjectMembersLimit, Pointer: "/c"},
+		{Title: "Memory", Input: `["` + strings.Repeat("x", 1000) + `"]`, Limits: Limits{MaxTreeMemory: 512}, Limit: TreeMemoryLimit, Pointer: "/0"},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Title, func(t *testing.T) {
+			_, err := NewJsonParseNode([]byte(test.Input), WithLimits(test.Limits))
+			var limitErr *LimitExceededError
+			require.True(t, errors.As(err, &limitErr))
+			assert.Equal(t, test.Limit, limitErr.Limit)
+			var parseErr *ParseError
+			require.True(t, errors.As(err, &parseErr))
+			assert.Equal(t, test.Pointer, parseErr.Pointer)
+
+			_, err = NewJsonParseNode([]byte(test.Input))
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLimitsAllowPayloadsWithinBounds(t *testing.T) {
+	limits := Limits{MaxDepth: 2, MaxTokens: 9, MaxStringLength: 3, MaxArrayLength: 2, MaxObjectMembers: 2, MaxTreeMemory: 4096}
+	parseNode, err := NewJsonParseNodeFromReader(strings.NewReader(`{"a": [1, "abc"], "b": null}`), WithLimits(limits))
+	require.NoError(t, err)
+	assert.NotNil(t, parseNode)
+}
+
+func TestLimitsStopReadingEarly(t *testing.T) {
+	// the payload is never terminated, the depth limit must fail before the reader is exhausted
+	reader := strings.NewReader(strings.Repeat("[", 1<<20))
+	_, err := NewJsonParseNodeFromReader(reader, WithLimits(Limits{MaxDepth: 64}))
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, DepthLimit, limitErr.Limit)
+	assert.Equal(t, "depth limit of 64 exceeded", limitErr.Error())
+	assert.NotZero(t, reader.Len())
+}
@@ -0,0 +1,262 @@
+package jsonserialization
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// CreateMergePatch returns the RFC 7386 JSON Merge Patch turning the JSON form of original into the JSON
+// form of modified. Members removed from objects are patched with nulls, objects present in both are
+// patched member by member and other changed values, arrays included, are replaced. Since nulls remove
+// members, members of modified that are set to null are removed by the patch. It returns "{}" when both
+// values have the same JSON form. The values and the patch are written with the options, so the patch
+// uses the same naming policy and encodings as the documents it applies to.
+func CreateMergePatch(original, modified absser.Parsable, opts ...JsonSerializationWriterOption) ([]byte, error) {
+	originalNode, err := untypedJsonOf(original, opts)
+	if err != nil {
+		return nil, err
+	}
+	modifiedNode, err := untypedJsonOf(modified, opts)
+	if err != nil {
+		return nil, err
+	}
+	patch, changed := mergePatchDiff(originalNode, modifiedNode)
+	if !changed {
+		patch = NewOrderedUntypedObject(nil, map[string]absser.UntypedNodeable{})
+	}
+	return writeUntypedJson(patch, opts...)
+}
+
+// ApplyMergePatch applies the RFC 7386 JSON Merge Patch to the target JSON document and returns the
+// resulting document. Members keep the order of the target, added members following in the order of the
+// patch, and numbers are written back as they were read.
+func ApplyMergePatch(target []byte, patch []byte) ([]byte, error) {
+	targetNode, err := parseUntypedJson(target)
+	if err != nil {
+		return nil, err
+	}
+	patchNode, err := parseUntypedJson(patch)
+	if err != nil {
+		return nil, err
+	}
+	return writeUntypedJson(mergePatch(targetNode, patchNode))
+}
+
+// untypedJsonOf returns the untyped node of the JSON form of a Parsable value written with the options.
+func untypedJsonOf(value absser.Parsable, opts []JsonSerializationWriterOption) (absser.UntypedNodeable, error) {
+	if isNil(value) {
+		return absser.NewUntypedNull(), nil
+	}
+	writer := NewJsonSerializationWriter(opts...)
+	defer writer.Close()
+	if err := writer.WriteObjectValue("", value); err != nil {
+		return nil, err
+	}
+	content, err := writer.GetSerializedContent()
+	if err != nil {
+		return nil, err
+	}
+	return parseUntypedJson(content)
+}
+
+// parseUntypedJson reads a JSON document as an untyped node, keeping the order of members and the text of
+// numbers.
+func parseUntypedJson(content []byte) (absser.UntypedNodeable, error) {
+	parseNode, err := NewJsonParseNode(content, WithLosslessRoundTrip())
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseNode.GetObjectValue(absser.CreateUntypedNodeFromDiscriminatorValue)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := value.(absser.UntypedNodeable)
+	if !ok {
+		return absser.NewUntypedNull(), nil
+	}
+	return node, nil
+}
+
+// writeUntypedJson returns the JSON document of an untyped node written with the options.
+func writeUntypedJson(node absser.UntypedNodeable, opts ...JsonSerializationWriterOption) ([]byte, error) {
+	writer := NewJsonSerializationWriter(opts...)
+	defer writer.Close()
+	if err := writer.WriteObjectValue("", node); err != nil {
+		return nil, err
+	}
+	return writer.GetSerializedContent()
+}
+
+// untypedMembers returns the keys in order and the members of an untyped object.
+func untypedMembers(node absser.UntypedNodeable) ([]string, map[string]absser.UntypedNodeable, bool) {
+	switch object := node.(type) {
+	case *OrderedUntypedObject:
+		return object.GetKeys(), object.GetValue(), true
+	case *absser.UntypedObject:
+		properties := object.GetValue()
+		return orderedKeys(nil, properties), properties, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// isUntypedNull reports whether an untyped node is a JSON null.
+func isUntypedNull(node absser.UntypedNodeable) bool {
+	if isNil(node) {
+		return true
+	}
+	_, ok := node.(*absser.UntypedNull)
+	return ok
+}
+
+// mergePatchDiff returns the merge patch turning original into modified, and whether they differ.
+func mergePatchDiff(original, modified absser.UntypedNodeable) (absser.UntypedNodeable, bool) {
+	originalKeys, originalMembers, originalIsObject := untypedMembers(original)
+	modifiedKeys, modifiedMembers, modifiedIsObject := untypedMembers(modified)
+	if !originalIsObject || !modifiedIsObject {
+		if untypedNodesEqual(original, modified) {
+			return nil, false
+		}
+		return modified, true
+	}
+	keys := make([]string, 0, len(modifiedKeys))
+	members := make(map[string]absser.UntypedNodeable)
+	for _, key := range modifiedKeys {
+		modifiedMember := modifiedMembers[key]
+		originalMember, ok := originalMembers[key]
+		if !ok {
+			if !isUntypedNull(modifiedMember) {
+				keys = append(keys, key)
+				members[key] = modifiedMember
+			}
+			continue
+		}
+		if patch, changed := mergePatchDiff(originalMember, modifiedMember); changed {
+			keys = append(keys, key)
+			members[key] = patch
+		}
+	}
+	for _, key := range originalKeys {
+		if _, ok := modifiedMembers[key]; !ok && !isUntypedNull(originalMembers[key]) {
+			keys = append(keys, key)
+			members[key] = absser.NewUntypedNull()
+		}
+	}
+	if len(keys) == 0 {
+		return nil, false
+	}
+	return NewOrderedUntypedObject(keys, members), true
+}
+
+// mergePatch applies a merge patch to the target, nil when the target is absent, as RFC 7386 defines it.
+func mergePatch(target, patch absser.UntypedNodeable) absser.UntypedNodeable {
+	patchKeys, patchMembers, patchIsObject := untypedMembers(patch)
+	if !patchIsObject {
+		return patch
+	}
+	targetKeys, targetMembers, targetIsObject := untypedMembers(target)
+	if !targetIsObject {
+		targetKeys, targetMembers = nil, nil
+	}
+	keys := make([]string, 0, len(targetKeys)+len(patchKeys))
+	members := make(map[string]absser.UntypedNodeable, len(targetMembers)+len(patchMembers))
+	for _, key := range targetKeys {
+		keys = append(keys, key)
+		members[key] = targetMembers[key]
+	}
+	for _, key := range patchKeys {
+		value := patchMembers[key]
+		current, exists := members[key]
+		if isUntypedNull(value) {
+			delete(members, key)
+			continue
+		}
+		if !exists {
+			keys = append(keys, key)
+		}
+		members[key] = mergePatch(current, value)
+	}
+	return NewOrderedUntypedObject(keys, members)
+}
+
+// untypedNodesEqual compares two untyped nodes deeply, objects regardless of the order of their members
+// and numbers by value.
+func untypedNodesEqual(left, right absser.UntypedNodeable) bool {
+	if isUntypedNull(left) || isUntypedNull(right) {
+		return isUntypedNull(left) && isUntypedNull(right)
+	}
+	if _, leftMembers, ok := untypedMembers(left); ok {
+		_, rightMembers, ok := untypedMembers(right)
+		if !ok || len(leftMembers) != len(rightMembers) {
+			return false
+		}
+		for key, leftMember := range leftMembers {
+			rightMember, ok := rightMembers[key]
+			if !ok || !untypedNodesEqual(leftMember, rightMember) {
+				return false
+			}
+		}
+		return true
+	}
+	if leftArray, ok := left.(*absser.UntypedArray); ok {
+		rightArray, ok := right.(*absser.UntypedArray)
+		if !ok || len(leftArray.GetValue()) != len(rightArray.GetValue()) {
+			return false
+		}
+		for i, element := range leftArray.GetValue() {
+			if !untypedNodesEqual(element, rightArray.GetValue()[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	leftNumber, leftIsNumber := untypedNumber(left)
+	rightNumber, rightIsNumber := untypedNumber(right)
+	if leftIsNumber || rightIsNumber {
+		return leftIsNumber && rightIsNumber && leftNumber == rightNumber
+	}
+	switch l := left.(type) {
+	case *absser.UntypedString:
+		r, ok := right.(*absser.UntypedString)
+		return ok && reflect.DeepEqual(l.GetValue(), r.GetValue())
+	case *absser.UntypedBoolean:
+		r, ok := right.(*absser.UntypedBoolean)
+		return ok && reflect.DeepEqual(l.GetValue(), r.GetValue())
+	case *absser.UntypedNode:
+		r, ok := right.(*absser.UntypedNode)
+		return ok && reflect.DeepEqual(l.GetValue(), r.GetValue())
+	default:
+		return false
+	}
+}
+
+// untypedNumber returns the value of an untyped number.
+func untypedNumber(node absser.UntypedNodeable) (decimalNumber, bool) {
+	text, ok := untypedNumberText(node)
+	if !ok {
+		return decimalNumber{}, false
+	}
+	return parseDecimalNumber(text)
+}
+
+// untypedNumberText returns the text of an untyped number.
+func untypedNumberText(node absser.UntypedNodeable) (string, bool) {
+	switch value := node.(type) {
+	case *absser.UntypedInteger:
+		return strconv.FormatInt(int64(*value.GetValue()), 10), true
+	case *absser.UntypedLong:
+		return strconv.FormatInt(*value.GetValue(), 10), true
+	case *absser.UntypedFloat:
+		return strconv.FormatFloat(float64(*value.GetValue()), 'g', -1, 32), true
+	case *absser.UntypedDouble:
+		return strconv.FormatFloat(*value.GetValue(), 'g', -1, 64), true
+	case *absser.UntypedNode:
+		number, ok := value.GetValue().(json.Number)
+		return number.String(), ok
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,140 @@
+package jsonserialization
+
+import (
+	"testing"
+	"time"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	// the examples of appendix A of RFC 7386
+	tests := []struct {
+		Target   string
+		Patch    string
+		Expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"big":12345678901234567890,"z":1}`, `{"z":2.50}`, `{"big":12345678901234567890,"z":2.50}`},
+	}
+	for _, test := range tests {
+		t.Run(test.Target+" "+test.Patch, func(t *testing.T) {
+			result, err := ApplyMergePatch([]byte(test.Target), []byte(test.Patch))
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, string(result))
+		})
+	}
+
+	_, err := ApplyMergePatch([]byte(`{"a":`), []byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	id, office, otherOffice := "1", "Redmond", "Paris"
+	original := internal.NewTestEntity()
+	original.SetId(&id)
+	original.SetOfficeLocation(&office)
+	sensitivity := internal.PRIVATE_SENSITIVITY
+	original.SetSensitivity(&sensitivity)
+
+	modified := internal.NewTestEntity()
+	modified.SetId(&id)
+	modified.SetOfficeLocation(&otherOffice)
+
+	patch, err := CreateMergePatch(original, modified)
+	require.NoError(t, err)
+	assert.Equal(t, `{"officeLocation":"Paris","sensitivity":null}`, string(patch))
+
+	patch, err = CreateMergePatch(original, original)
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(patch))
+
+	patch, err = CreateMergePatch(original, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `null`, string(patch))
+}
+
+func TestCreateMergePatchWithWriterOptions(t *testing.T) {
+	id, office, otherOffice := "1", "Redmond", "Paris"
+	created := time.Date(2023, 7, 12, 6, 54, 24, 0, time.UTC)
+	original := internal.NewTestEntity()
+	original.SetId(&id)
+	original.SetOfficeLocation(&office)
+
+	modified := internal.NewTestEntity()
+	modified.SetId(&id)
+	modified.SetOfficeLocation(&otherOffice)
+	modified.SetCreatedDateTime(&created)
+
+	patch, err := CreateMergePatch(original, modified, WithNamingPolicy(SnakeCaseNamingPolicy), WithTimeFormat(TimeFormatUnixSeconds))
+	require.NoError(t, err)
+	assert.Equal(t, `{"office_location":"Paris","created_date_time":1689144864}`, string(patch))
+}
+
+func TestMergePatchComparesNumbersByValue(t *testing.T) {
+	result, err := ApplyMergePatch([]byte(`{"a":1e1000000000,"b":[1.50]}`), []byte(`{"c":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1e1000000000,"b":[1.50],"c":1}`, string(result))
+
+	tests := []struct {
+		Left, Right string
+		Equal       bool
+	}{
+		{`1.50`, `15e-1`, true},
+		{`-0`, `0.0e5`, true},
+		{`100`, `1e2`, true},
+		{`1e1000000000`, `10e999999999`, true},
+		{`1e1000000000`, `1e1000000001`, false},
+		{`1`, `-1`, false},
+	}
+	for _, test := range tests {
+		left, err := parseUntypedJson([]byte(test.Left))
+		require.NoError(t, err)
+		right, err := parseUntypedJson([]byte(test.Right))
+		require.NoError(t, err)
+		assert.Equal(t, test.Equal, untypedNodesEqual(left, right), test.Left+" "+test.Right)
+	}
+}
+
+func TestCreateMergePatchOfNestedObjects(t *testing.T) {
+	original := absser.NewUntypedObject(map[string]absser.UntypedNodeable{
+		"name":    absser.NewUntypedString("root"),
+		"count":   absser.NewUntypedInteger(1),
+		"nested":  absser.NewUntypedObject(map[string]absser.UntypedNodeable{"a": absser.NewUntypedString("b"), "c": absser.NewUntypedString("d")}),
+		"list":    absser.NewUntypedArray([]absser.UntypedNodeable{absser.NewUntypedLong(1)}),
+		"removed": absser.NewUntypedBoolean(true),
+	})
+	modified := absser.NewUntypedObject(map[string]absser.UntypedNodeable{
+		"name":   absser.NewUntypedString("root"),
+		"count":  absser.NewUntypedDouble(1),
+		"nested": absser.NewUntypedObject(map[string]absser.UntypedNodeable{"a": absser.NewUntypedString("b"), "c": absser.NewUntypedString("e")}),
+		"list":   absser.NewUntypedArray([]absser.UntypedNodeable{absser.NewUntypedLong(1), absser.NewUntypedLong(2)}),
+		"added":  absser.NewUntypedNull(),
+	})
+	patch, err := CreateMergePatch(original, modified)
+	require.NoError(t, err)
+	assert.Equal(t, `{"list":[1,2],"nested":{"c":"e"},"removed":null}`, string(patch))
+
+	originalContent, err := writeUntypedJson(original)
+	require.NoError(t, err)
+	result, err := ApplyMergePatch(originalContent, patch)
+	require.NoError(t, err)
+	assert.Equal(t, `{"count":1,"list":[1,2],"name":"root","nested":{"a":"b","c":"e"}}`, string(result))
+}
@@ -0,0 +1,160 @@
+package jsonserialization
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// NamingPolicy converts the name of a property of a model to the name of its member in payloads.
+type NamingPolicy func(name string) string
+
+// WithNamingPolicy makes writers convert the names of the properties of models with the policy, and
+// GetObjectValue match the members of payloads against the converted names of the fields returned by
+// GetFieldDeserializers. Additional data, untyped objects and discriminators are written with their names
+// as they are, and members matching no converted name are read as additional data.
+func WithNamingPolicy(policy NamingPolicy) JsonOption {
+	if policy == nil {
+		return namingPolicyOption{}
+	}
+	return namingPolicyOption{policy: &namingPolicy{convert: policy}}
+}
+
+type namingPolicyOption struct {
+	policy *namingPolicy
+}
+
+func (o namingPolicyOption) applyToParseNode(options *parseNodeOptions) {
+	options.namingPolicy = o.policy
+}
+
+func (o namingPolicyOption) applyToSerializationWriter(options *serializationWriterOptions) {
+	options.namingPolicy = o.policy
+}
+
+// namingPolicy is a naming policy remembering the names it converted, shared by the nodes and writers
+// created with the option.
+type namingPolicy struct {
+	convert NamingPolicy
+	names   sync.Map
+}
+
+// name returns the converted name.
+func (p *namingPolicy) name(name string) string {
+	if converted, ok := p.names.Load(name); ok {
+		return converted.(string)
+	}
+	converted := p.convert(name)
+	p.names.Store(name, converted)
+	return converted
+}
+
+// CamelCaseNamingPolicy converts names to camelCase, "display_name" becoming "displayName".
+func CamelCaseNamingPolicy(name string) string {
+	return joinWords(name, "", func(i int, word string) string {
+		if i == 0 {
+			return strings.ToLower(word)
+		}
+		return titleWord(word)
+	})
+}
+
+// PascalCaseNamingPolicy converts names to PascalCase, "displayName" becoming "DisplayName".
+func PascalCaseNamingPolicy(name string) string {
+	return joinWords(name, "", func(_ int, word string) string {
+		return titleWord(word)
+	})
+}
+
+// SnakeCaseNamingPolicy converts names to snake_case, "displayName" becoming "display_name".
+func SnakeCaseNamingPolicy(name string) string {
+	return joinWords(name, "_", func(_ int, word string) string {
+		return strings.ToLower(word)
+	})
+}
+
+// KebabCaseNamingPolicy converts names to kebab-case, "displayName" becoming "display-name".
+func KebabCaseNamingPolicy(name string) string {
+	return joinWords(name, "-", func(_ int, word string) string {
+		return strings.ToLower(word)
+	})
+}
+
+// joinWords returns the words of a name, converted, joined with the separator. Annotations, whose names
+// start with @, are returned as they are.
+func joinWords(name, separator string, convert func(i int, word string) string) string {
+	if strings.HasPrefix(name, "@") {
+		return name
+	}
+	words := splitWords(name)
+	for i, word := range words {
+		words[i] = convert(i, word)
+	}
+	return strings.Join(words, separator)
+}
+
+// splitWords returns the words of a name, separated by underscores, hyphens, spaces and changes of case.
+// An uppercase run is a word of its own, "HTTPServer" being "HTTP" and "Server".
+func splitWords(name string) []string {
+	runes := []rune(name)
+	words := make([]string, 0, 4)
+	start := -1
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// titleWord returns the word with its first letter in uppercase and the others in lowercase.
+func titleWord(word string) string {
+	first, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(first)) + strings.ToLower(word[size:])
+}
+
+// namedFieldDeserializers returns the deserializers of the fields keyed by the names of their members in
+// payloads.
+func (n *JsonParseNode) namedFieldDeserializers(fields map[string]func(absser.ParseNode) error) map[string]func(absser.ParseNode) error {
+	policy := n.getOptions().namingPolicy
+	if policy == nil || len(fields) == 0 {
+		return fields
+	}
+	named := make(map[string]func(absser.ParseNode) error, len(fields))
+	for name, field := range fields {
+		named[policy.name(name)] = field
+	}
+	return named
+}
+
+// propertyName returns the name of a property in the content, converted with the naming policy unless
+// the name is written as it is.
+func (w *JsonSerializationWriter) propertyName(key string) string {
+	policy := w.getOptions().namingPolicy
+	if policy == nil || w.rawPropertyName {
+		w.rawPropertyName = false
+		return key
+	}
+	return policy.name(key)
+}
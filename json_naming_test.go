@@ -0,0 +1,112 @@
+package jsonserialization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+)
+
+func TestNamingPolicies(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Camel  string
+		Pascal string
+		Snake  string
+		Kebab  string
+	}{
+		{"displayName", "displayName", "DisplayName", "display_name", "display-name"},
+		{"DisplayName", "displayName", "DisplayName", "display_name", "display-name"},
+		{"display_name", "displayName", "DisplayName", "display_name", "display-name"},
+		{"display-name", "displayName", "DisplayName", "display_name", "display-name"},
+		{"HTTPServerURL", "httpServerUrl", "HttpServerUrl", "http_server_url", "http-server-url"},
+		{"address2Line", "address2Line", "Address2Line", "address2_line", "address2-line"},
+		{"id", "id", "Id", "id", "id"},
+		{"@odata.type", "@odata.type", "@odata.type", "@odata.type", "@odata.type"},
+		{"", "", "", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Camel, CamelCaseNamingPolicy(test.Name))
+			assert.Equal(t, test.Pascal, PascalCaseNamingPolicy(test.Name))
+			assert.Equal(t, test.Snake, SnakeCaseNamingPolicy(test.Name))
+			assert.Equal(t, test.Kebab, KebabCaseNamingPolicy(test.Name))
+		})
+	}
+}
+
+func TestWriteWithNamingPolicy(t *testing.T) {
+	entity := internal.NewTestEntity()
+	id, officeLocation := "1", "Montreal"
+	entity.SetId(&id)
+	entity.SetOfficeLocation(&officeLocation)
+	entity.SetBirthDay(absser.NewDateOnly(referenceTime()))
+
+	writer := NewJsonSerializationWriter(WithNamingPolicy(SnakeCaseNamingPolicy))
+	defer writer.Close()
+	require.NoError(t, writer.WriteObjectValue("", entity))
+	content, err := writer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1","office_location":"Montreal","birth_day":"2006-01-02"}`, string(content))
+}
+
+func TestWriteWithNamingPolicyKeepsUntypedNames(t *testing.T) {
+	entity := internal.NewUntypedTestEntity()
+	title := "Title"
+	entity.SetTitle(&title)
+	streetName := "Rue"
+	entity.SetLocation(absser.NewUntypedObject(map[string]absser.UntypedNodeable{
+		"streetName": absser.NewUntypedString(streetName),
+	}))
+	entity.SetAdditionalData(map[string]interface{}{"extraData": true})
+
+	writer := NewJsonSerializationWriter(WithNamingPolicy(KebabCaseNamingPolicy))
+	defer writer.Close()
+	require.NoError(t, writer.WriteObjectValue("", entity))
+	content, err := writer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"title":"Title","location":{"streetName":"Rue"},"extraData":true}`, string(content))
+}
+
+func TestNamingPolicyEscapesNames(t *testing.T) {
+	policy := WithNamingPolicy(func(name string) string {
+		return name + "\"\r\\<"
+	})
+	entity := internal.NewTestEntity()
+	id, officeLocation := "1", "Montreal"
+	entity.SetId(&id)
+	entity.SetOfficeLocation(&officeLocation)
+
+	writer := NewJsonSerializationWriter(policy)
+	defer writer.Close()
+	require.NoError(t, writer.WriteObjectValue("", entity))
+	content, err := writer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"id\"\r\\<":"1","officeLocation\"\r\\<":"Montreal"}`, string(content))
+
+	node, err := NewJsonParseNode(content, policy)
+	require.NoError(t, err)
+	result, err := node.GetObjectValue(internal.CreateTestEntityFromDiscriminator)
+	require.NoError(t, err)
+	parsed := result.(*internal.TestEntity)
+	assert.Equal(t, "1", *parsed.GetId())
+	assert.Equal(t, "Montreal", *parsed.GetOfficeLocation())
+	assert.Empty(t, parsed.GetAdditionalData())
+}
+
+func TestReadWithNamingPolicy(t *testing.T) {
+	content := `{"id":"1","office_location":"Montreal","officeLocation":"Quebec","birth_day":"2017-09-04"}`
+	node, err := NewJsonParseNode([]byte(content), WithNamingPolicy(SnakeCaseNamingPolicy))
+	require.NoError(t, err)
+	result, err := node.GetObjectValue(internal.CreateTestEntityFromDiscriminator)
+	require.NoError(t, err)
+
+	entity := result.(*internal.TestEntity)
+	assert.Equal(t, "1", *entity.GetId())
+	assert.Equal(t, "Montreal", *entity.GetOfficeLocation())
+	assert.Equal(t, "2017-09-04", entity.GetBirthDay().String())
+	assert.Equal(t, map[string]interface{}{"officeLocation": ref("Quebec")}, entity.GetAdditionalData())
+}
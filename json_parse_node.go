@@ -3,12 +3,14 @@
 package jsonserialization
 
 import (
-	"bytes"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,182 +24,383 @@ type JsonParseNode struct {
 	value                     interface{}
 	onBeforeAssignFieldValues absser.ParsableAction
 	onAfterAssignFieldValues  absser.ParsableAction
+	// parent is the node the node was reached from, nil for a root. With key and index, it gives
+	// the JSON Pointer of the node, which is only computed when an error is reported.
+	parent *JsonParseNode
+	// key is the key of the node in its parent object.
+	key string
+	// index is the index of the node in its parent array, -1 when the parent is an object.
+	index int
+	// offset is the byte offset of the value in the payload it was read from, -1 when it is unknown.
+	offset int64
+	// source maps offsets to lines and columns, nil when the node was not read from a payload.
+	source *jsonSource
+	// members holds the keys of the members in payload order and the offsets of their values when the node is an object.
+	members []memberOffset
+	// elementOffsets holds the offsets of the elements when the node is an array.
+	elementOffsets []int64
+	// options holds the settings of the tree, nil for the defaults.
+	options *parseNodeOptions
+	// collector gathers the failures of the read in progress in collect-all-errors mode.
+	collector *errorCollector
 }
 
-// tokenToValue converts a JSON token to either a raw primitive value (to avoid JsonParseNode
-// allocation for primitives) or a *JsonParseNode for complex types (objects and arrays).
-// This is used when building parse trees to reduce allocations.
-func tokenToValue(decoder *json.Decoder, token json.Token) (interface{}, error) {
-	switch t := token.(type) {
-	case json.Delim:
-		node, err := loadJsonTreeFromToken(decoder, t)
-		return node, err
-	case float64:
-		f := t
-		return &f, nil
-	case string:
-		s := t
-		return &s, nil
-	case bool:
-		b := t
-		return &b, nil
-	case json.Number:
-		i, err := t.Int64()
-		if err == nil {
-			return &i, nil
-		}
-		f, err := t.Float64()
-		if err == nil {
-			return &f, nil
-		}
-		return nil, fmt.Errorf("failed to parse number %q: %w", t, err)
-	case int8:
-		v := t
-		return &v, nil
-	case byte:
-		v := t
-		return &v, nil
-	case float32:
-		v := t
-		return &v, nil
-	case int32:
-		v := t
-		return &v, nil
-	case int64:
-		v := t
-		return &v, nil
-	case nil:
-		return nil, nil
-	default:
-		return nil, fmt.Errorf("unknown token type during parsing: %T", token)
-	}
+// treeLoader builds parse trees from the tokens of a payload. When the token reader tracks offsets,
+// the offset of every value is recorded so getters can report where a faulty value is located.
+type treeLoader struct {
+	tokens  tokenReader
+	options *parseNodeOptions
+	usage   loadUsage
+	// path locates the value being loaded.
+	path []pathElement
+	// openMembers and openElements hold the children of the containers being loaded.
+	openMembers  []memberOffset
+	openElements []loadedElement
+	// the buffers back path, openMembers and openElements for small payloads.
+	pathBuf         [16]pathElement
+	openMembersBuf  [16]memberOffset
+	openElementsBuf [32]loadedElement
+	// members and elementOffsets back the member and element offsets of every node of the tree,
+	// so that a container does not cost an allocation of its own for them.
+	members        []memberOffset
+	elementOffsets []int64
+	// content is the payload being loaded when nested containers are skipped, see WithLazyLoading.
+	content []byte
+	// root is the node whose subtree is being loaded, nil when loading a whole payload.
+	root *JsonParseNode
+}
+
+// treeLoaders holds the loaders that are done loading, so their buffers are not allocated for every load.
+var treeLoaders = sync.Pool{New: func() interface{} { return new(treeLoader) }}
+
+// newTreeLoader returns a loader reading the tokens of tokens. It must be released once the tree is loaded.
+func newTreeLoader(tokens tokenReader, options *parseNodeOptions) *treeLoader {
+	l := treeLoaders.Get().(*treeLoader)
+	l.tokens = tokens
+	l.options = options
+	l.path = l.pathBuf[:0]
+	l.openMembers = l.openMembersBuf[:0]
+	l.openElements = l.openElementsBuf[:0]
+	return l
+}
+
+// release returns the loader to the pool. The nodes it loaded keep their own member and element
+// offsets, and the buffers that outgrew the inline ones are dropped.
+func (l *treeLoader) release() {
+	*l = treeLoader{}
+	treeLoaders.Put(l)
+}
+
+// pathElement is a step of the path to the value being loaded, a member key or an element index.
+type pathElement struct {
+	key string
+	// index is the index of the element, -1 for a member.
+	index int
+}
+
+// memberOffset is the key of an object member and the offset of its value.
+type memberOffset struct {
+	key    string
+	offset int64
+}
+
+// loadedElement is an element of an array being loaded.
+type loadedElement struct {
+	value  interface{}
+	offset int64
 }
 
 // NewJsonParseNode creates a new JsonParseNode.
-func NewJsonParseNode(content []byte) (*JsonParseNode, error) {
+func NewJsonParseNode(content []byte, opts ...JsonParseNodeOption) (*JsonParseNode, error) {
 	if len(content) == 0 {
 		return nil, errors.New("content is empty")
 	}
-	if !json.Valid(content) {
-		return nil, errors.New("invalid json type")
+	options := newParseNodeOptions(opts)
+	loader := newTreeLoader(newBytesScanner(content), options)
+	defer loader.release()
+	if options != nil && options.lazyLoading {
+		loader.content = content
 	}
-	decoder := json.NewDecoder(bytes.NewReader(content))
-	value, err := loadJsonTree(decoder)
-	return value, err
+	return loader.loadTree()
 }
 
-func loadJsonTree(decoder *json.Decoder) (*JsonParseNode, error) {
-	token, err := decoder.Token()
+// NewJsonParseNodeFromReader creates a new JsonParseNode by decoding the content of the reader.
+// The tree is built in a single pass without buffering the whole payload first, and syntax errors
+// are reported as soon as they are reached.
+func NewJsonParseNodeFromReader(reader io.Reader, opts ...JsonParseNodeOption) (*JsonParseNode, error) {
+	if reader == nil {
+		return nil, errors.New("reader is nil")
+	}
+	loader := newTreeLoader(newReaderScanner(reader), newParseNodeOptions(opts))
+	defer loader.release()
+	return loader.loadTree()
+}
+
+// loadTree reads the single root value of the content.
+func (l *treeLoader) loadTree() (*JsonParseNode, error) {
+	token, err := l.nextToken()
 	if err == io.EOF {
-		return nil, nil
+		return nil, errors.New("content is empty")
+	}
+	if err != nil {
+		return nil, l.wrapError(err)
 	}
+	value, err := l.loadValue(&token)
 	if err != nil {
 		return nil, err
 	}
-	return loadJsonTreeFromToken(decoder, token)
-}
-
-// loadJsonTreeFromToken builds a JsonParseNode from an already-consumed token.
-// For object and array delimiters, it reads the remaining tokens from the decoder.
-// For primitive tokens it wraps the value directly in a JsonParseNode.
-// Primitive values inside objects and arrays are stored as raw values (not wrapped
-// in *JsonParseNode) to reduce allocations.
-func loadJsonTreeFromToken(decoder *json.Decoder, token json.Token) (*JsonParseNode, error) {
-	switch t := token.(type) {
-	case json.Delim:
-		switch t {
-		case '{':
-			v := make(map[string]interface{})
-			for decoder.More() {
-				key, err := decoder.Token()
-				if err != nil {
-					return nil, err
-				}
-				keyStr, ok := key.(string)
-				if !ok {
-					return nil, errors.New("key is not a string")
-				}
-				valToken, err := decoder.Token()
-				if err != nil {
-					return nil, err
-				}
-				childValue, err := tokenToValue(decoder, valToken)
-				if err != nil {
-					return nil, err
-				}
-				v[keyStr] = childValue
-			}
-			endTok, err := decoder.Token() // consume the closing curly
-			if err != nil {
-				return nil, err
+	// the root value must be the only value in the content
+	if token, err := l.nextToken(); err != io.EOF {
+		if err != nil {
+			return nil, l.wrapError(err)
+		}
+		return nil, newParseErrorAt("", l.source(), token.offset, nil, "",
+			fmt.Errorf("invalid json: unexpected content after offset %d", token.offset))
+	}
+	if node, ok := value.(*JsonParseNode); ok || value == nil {
+		return node, nil
+	}
+	return l.newNode(value, token.offset), nil
+}
+
+// pointer returns the JSON Pointer of the value being loaded. It is only computed when an error is reported.
+func (l *treeLoader) pointer() string {
+	pointer := ""
+	if l.root != nil {
+		pointer = l.root.pointer()
+	}
+	for _, element := range l.path {
+		if element.index < 0 {
+			pointer = childPointer(pointer, element.key)
+		} else {
+			pointer = elementPointer(pointer, element.index)
+		}
+	}
+	return pointer
+}
+
+// nextToken reads the next token and checks it against the limits of the tree.
+func (l *treeLoader) nextToken() (jsonToken, error) {
+	token, err := l.tokens.readToken()
+	if err != nil {
+		return token, err
+	}
+	if err := l.useToken(&token); err != nil {
+		return token, err
+	}
+	return token, nil
+}
+
+// wrapError adds the location of a syntax error to the error when offsets are tracked.
+func (l *treeLoader) wrapError(err error) error {
+	source := l.source()
+	if source == nil {
+		return err
+	}
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return err
+	}
+	offset := l.tokens.inputOffset()
+	var syntaxErr *syntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.offset
+	}
+	return newParseErrorAt(l.pointer(), source, offset, nil, "", err)
+}
+
+// loadValue returns the value starting with token, a *JsonParseNode for objects and arrays or a raw primitive.
+func (l *treeLoader) loadValue(token *jsonToken) (interface{}, error) {
+	switch token.kind {
+	case tokenBeginObject:
+		if l.content != nil && l.usage.depth > 0 {
+			node, err := l.skipContainer(token.offset)
+			return node, err
+		}
+		node, err := l.loadContainer('{', token.offset)
+		return node, err
+	case tokenBeginArray:
+		if l.content != nil && l.usage.depth > 0 {
+			node, err := l.skipContainer(token.offset)
+			return node, err
+		}
+		node, err := l.loadContainer('[', token.offset)
+		return node, err
+	case tokenString:
+		s := token.stringValue()
+		return &s, nil
+	case tokenNumber:
+		n := json.Number(token.text)
+		return &n, nil
+	case tokenTrue, tokenFalse:
+		b := token.kind == tokenTrue
+		return &b, nil
+	case tokenNull:
+		return nil, nil
+	default:
+		return nil, l.wrapError(errors.New("unexpected end of container"))
+	}
+}
+
+// getOptions returns the settings of the tree being loaded.
+func (l *treeLoader) getOptions() *parseNodeOptions {
+	if l.options == nil {
+		return &defaultParseNodeOptions
+	}
+	return l.options
+}
+
+// source returns the source of the payload being loaded, nil when offsets are not tracked.
+func (l *treeLoader) source() *jsonSource {
+	return l.tokens.source()
+}
+
+// newNode creates a node located at offset in the payload being loaded.
+func (l *treeLoader) newNode(value interface{}, offset int64) *JsonParseNode {
+	return &JsonParseNode{value: value, offset: offset, source: l.source(), options: l.options}
+}
+
+// loadContainer loads the members of an object or the elements of an array, the opening delimiter being already read.
+func (l *treeLoader) loadContainer(delim json.Delim, offset int64) (*JsonParseNode, error) {
+	if err := l.enterContainer(offset); err != nil {
+		return nil, err
+	}
+	defer l.exitContainer()
+	switch delim {
+	case '{':
+		return l.loadObject(offset)
+	case '[':
+		return l.loadArray(offset)
+	default:
+		return nil, l.wrapError(fmt.Errorf("unexpected delimiter token: %v", delim))
+	}
+}
+
+func (l *treeLoader) loadObject(offset int64) (*JsonParseNode, error) {
+	v := make(map[string]interface{})
+	// the members of the object are gathered after the ones of the enclosing objects and moved to
+	// the members of the tree once the object is complete
+	first := len(l.openMembers)
+	var collected map[string]*JsonParseNode
+	for {
+		key, err := l.nextToken()
+		if err != nil {
+			return nil, l.wrapError(err)
+		}
+		if key.kind == tokenEndObject {
+			break
+		}
+		if key.kind != tokenString {
+			return nil, l.wrapError(errors.New("key is not a string"))
+		}
+		keyStr := key.stringValue()
+		l.path = append(l.path, pathElement{key: keyStr, index: -1})
+		if err := l.useMember(len(l.openMembers)-first+1, keyStr, key.offset); err != nil {
+			return nil, err
+		}
+		valToken, err := l.nextToken()
+		if err != nil {
+			return nil, l.wrapError(err)
+		}
+		childValue, err := l.loadValue(&valToken)
+		if err != nil {
+			return nil, err
+		}
+		existing, duplicate := v[keyStr]
+		if !duplicate {
+			v[keyStr] = childValue
+			l.openMembers = append(l.openMembers, memberOffset{key: keyStr, offset: valToken.offset})
+			l.path = l.path[:len(l.path)-1]
+			continue
+		}
+		previous := &l.openMembers[first]
+		for i := len(l.openMembers) - 1; i > first; i-- {
+			if l.openMembers[i].key == keyStr {
+				previous = &l.openMembers[i]
+				break
 			}
-			if d, ok := endTok.(json.Delim); !ok || d != '}' {
-				return nil, fmt.Errorf("expected closing '}', got %v", endTok)
+		}
+		switch l.getOptions().duplicateKeyPolicy {
+		case DuplicateKeyFirstWins:
+			// the value was only read to move past it
+		case DuplicateKeyError:
+			return nil, newParseErrorAt(l.pointer(), l.source(), key.offset, childValue, "", fmt.Errorf("%w %q", ErrDuplicateKey, keyStr))
+		case DuplicateKeyCollect:
+			if collected == nil {
+				collected = make(map[string]*JsonParseNode)
 			}
-			return &JsonParseNode{value: v}, nil
-		case '[':
-			v := make([]interface{}, 0)
-			for decoder.More() {
-				elemToken, err := decoder.Token()
-				if err != nil {
-					return nil, err
-				}
-				elem, err := tokenToValue(decoder, elemToken)
-				if err != nil {
-					return nil, err
+			values, ok := collected[keyStr]
+			if !ok {
+				values = l.newNode([]interface{}{existing}, previous.offset)
+				if l.source() != nil {
+					values.elementOffsets = []int64{previous.offset}
 				}
-				v = append(v, elem)
+				collected[keyStr] = values
+				v[keyStr] = values
 			}
-			endTok, err := decoder.Token() // consume the closing bracket
-			if err != nil {
-				return nil, err
-			}
-			if d, ok := endTok.(json.Delim); !ok || d != ']' {
-				return nil, fmt.Errorf("expected closing ']', got %v", endTok)
+			values.value = append(values.value.([]interface{}), childValue)
+			if l.source() != nil {
+				values.elementOffsets = append(values.elementOffsets, valToken.offset)
 			}
-			return &JsonParseNode{value: v}, nil
 		default:
-			return nil, fmt.Errorf("unexpected delimiter token: %v", t)
-		}
-	case json.Number:
-		i, err := t.Int64()
-		if err == nil {
-			return &JsonParseNode{value: &i}, nil
-		}
-		f, err := t.Float64()
-		if err == nil {
-			return &JsonParseNode{value: &f}, nil
-		}
-		return nil, fmt.Errorf("failed to parse number %q: %w", t, err)
-	case string:
-		s := t
-		return &JsonParseNode{value: &s}, nil
-	case bool:
-		b := t
-		return &JsonParseNode{value: &b}, nil
-	case int8:
-		v := t
-		return &JsonParseNode{value: &v}, nil
-	case byte:
-		v := t
-		return &JsonParseNode{value: &v}, nil
-	case float64:
-		f := t
-		return &JsonParseNode{value: &f}, nil
-	case float32:
-		v := t
-		return &JsonParseNode{value: &v}, nil
-	case int32:
-		v := t
-		return &JsonParseNode{value: &v}, nil
-	case int64:
-		v := t
-		return &JsonParseNode{value: &v}, nil
-	case nil:
-		return nil, nil
-	default:
-		return nil, fmt.Errorf("unknown token type during tree loading: %T", token)
+			v[keyStr] = childValue
+			previous.offset = valToken.offset
+		}
+		l.path = l.path[:len(l.path)-1]
+	}
+	node := l.newNode(v, offset)
+	if len(l.openMembers) > first {
+		start := len(l.members)
+		l.members = append(l.members, l.openMembers[first:]...)
+		node.members = l.members[start:len(l.members):len(l.members)]
+		l.openMembers = l.openMembers[:first]
+	}
+	return node, nil
+}
+
+func (l *treeLoader) loadArray(offset int64) (*JsonParseNode, error) {
+	// the elements are gathered after the ones of the enclosing arrays and copied to a slice of the
+	// right size once the array is complete
+	first := len(l.openElements)
+	tracked := l.source() != nil
+	for index := 0; ; index++ {
+		l.path = append(l.path, pathElement{index: index})
+		elemToken, err := l.nextToken()
+		if err != nil {
+			// the token may be the end of the array as well as an element
+			l.path = l.path[:len(l.path)-1]
+			return nil, l.wrapError(err)
+		}
+		if elemToken.kind == tokenEndArray {
+			l.path = l.path[:len(l.path)-1]
+			break
+		}
+		if err := l.useElement(index+1, elemToken.offset); err != nil {
+			return nil, err
+		}
+		elem, err := l.loadValue(&elemToken)
+		if err != nil {
+			return nil, err
+		}
+		l.openElements = append(l.openElements, loadedElement{value: elem, offset: elemToken.offset})
+		l.path = l.path[:len(l.path)-1]
+	}
+	elements := l.openElements[first:]
+	v := make([]interface{}, len(elements))
+	for i, element := range elements {
+		v[i] = element.value
+	}
+	node := l.newNode(v, offset)
+	if tracked && len(elements) > 0 {
+		start := len(l.elementOffsets)
+		for _, element := range elements {
+			l.elementOffsets = append(l.elementOffsets, element.offset)
+		}
+		node.elementOffsets = l.elementOffsets[start:len(l.elementOffsets):len(l.elementOffsets)]
 	}
+	clear(elements)
+	l.openElements = l.openElements[:first]
+	return node, nil
 }
 
 // SetValue is obsolete, parse nodes are not meant to be settable externally
@@ -228,29 +431,117 @@ func (n *JsonParseNode) GetChildNode(index string) (absser.ParseNode, error) {
 		return nil, nil
 	}
 
-	var childNode *JsonParseNode
 	if rawChild == nil {
 		// JSON null value: return a typed nil so callers can still invoke methods on the
 		// returned ParseNode interface without a nil-interface panic.
 		return (*JsonParseNode)(nil), nil
-	} else if jn, ok := rawChild.(*JsonParseNode); ok {
-		childNode = jn
-	} else {
-		// Raw primitive value – wrap on demand to avoid pre-allocation
-		childNode = &JsonParseNode{value: rawChild}
 	}
+	return n.memberNode(index, rawChild)
+}
 
-	if childNode != nil {
-		err := childNode.SetOnBeforeAssignFieldValues(n.GetOnBeforeAssignFieldValues())
-		if err != nil {
-			return nil, err
-		}
-		err = childNode.SetOnAfterAssignFieldValues(n.GetOnAfterAssignFieldValues())
-		if err != nil {
-			return nil, err
+// memberNode returns the node of a member of the object, see wrapChild.
+func (n *JsonParseNode) memberNode(key string, rawValue interface{}) (*JsonParseNode, error) {
+	// the offset of a member is looked up when an error is reported
+	return n.wrapChild(rawValue, key, -1, -1)
+}
+
+// elementNode returns the node of an element of the array, see wrapChild.
+func (n *JsonParseNode) elementNode(index int, rawValue interface{}) (*JsonParseNode, error) {
+	return n.wrapChild(rawValue, "", index, n.elementOffset(index))
+}
+
+// pointer returns the JSON Pointer of the node in the payload it was read from.
+func (n *JsonParseNode) pointer() string {
+	if n.parent == nil {
+		return ""
+	}
+	if n.index >= 0 {
+		return elementPointer(n.parent.pointer(), n.index)
+	}
+	return childPointer(n.parent.pointer(), n.key)
+}
+
+// valueOffset returns the offset of the value of the node, -1 when it is unknown.
+func (n *JsonParseNode) valueOffset() int64 {
+	if n.offset < 0 && n.parent != nil && n.index < 0 {
+		return n.parent.memberOffset(n.key)
+	}
+	return n.offset
+}
+
+// memberOffset returns the offset of the value of a member of the object, -1 when it is unknown.
+func (n *JsonParseNode) memberOffset(key string) int64 {
+	if n.source == nil {
+		return -1
+	}
+	for _, member := range n.members {
+		if member.key == key {
+			return member.offset
 		}
 	}
+	return -1
+}
+
+// memberKeys returns the keys of the members of the object in payload order.
+func (n *JsonParseNode) memberKeys() []string {
+	if len(n.members) == 0 {
+		return nil
+	}
+	keys := make([]string, len(n.members))
+	for i, member := range n.members {
+		keys[i] = member.key
+	}
+	return keys
+}
+
+// elementOffset returns the offset of an element of the array, -1 when it is unknown.
+func (n *JsonParseNode) elementOffset(index int) int64 {
+	if index < len(n.elementOffsets) {
+		return n.elementOffsets[index]
+	}
+	return -1
+}
 
+// elementParseError returns a ParseError for an element of the array that is stored as a raw primitive.
+func (n *JsonParseNode) elementParseError(index int, rawValue interface{}, expected string, err error) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return err
+	}
+	return newParseErrorAt(elementPointer(n.pointer(), index), n.source, n.elementOffset(index), rawValue, expected, err)
+}
+
+// wrapChild returns the node of the child value with the given key or index, index being -1 for members.
+// Raw primitives are wrapped on demand to avoid pre-allocation, and the hooks of the node are passed on to the child.
+func (n *JsonParseNode) wrapChild(rawValue interface{}, key string, index int, offset int64) (*JsonParseNode, error) {
+	if rawValue == nil {
+		return nil, nil
+	}
+	childNode, ok := rawValue.(*JsonParseNode)
+	if !ok {
+		childNode = &JsonParseNode{value: rawValue, offset: offset, source: n.source}
+	}
+	return n.adopt(childNode, key, index)
+}
+
+// adopt makes childNode the child of the node with the given key or index, loading it when it was skipped.
+func (n *JsonParseNode) adopt(childNode *JsonParseNode, key string, index int) (*JsonParseNode, error) {
+	childNode.parent = n
+	childNode.key = key
+	childNode.index = index
+	if err := childNode.materialize(); err != nil {
+		return nil, err
+	}
+	childNode.options = n.options
+	childNode.collector = n.collector
+	err := childNode.SetOnBeforeAssignFieldValues(n.GetOnBeforeAssignFieldValues())
+	if err != nil {
+		return nil, err
+	}
+	err = childNode.SetOnAfterAssignFieldValues(n.GetOnAfterAssignFieldValues())
+	if err != nil {
+		return nil, err
+	}
 	return childNode, nil
 }
 
@@ -262,9 +553,31 @@ func (n *JsonParseNode) GetObjectValue(ctor absser.ParsableFactory) (absser.Pars
 	if ctor == nil {
 		return nil, errors.New("constructor is nil")
 	}
+	collector, owner := n.startCollecting()
+	if collector == nil {
+		return n.getObjectValue(ctor, nil)
+	}
+	result, err := n.getObjectValue(ctor, collector)
+	if err != nil {
+		collector.add(n, err)
+	}
+	if owner {
+		// only the node the read started from reports the failures, nested objects are
+		// returned as they are so the partially populated result holds them
+		return result, collector.finish()
+	}
+	return result, nil
+}
+
+// getObjectValue reads the Parsable value of the node, reporting the failures of fields to the collector when one is set.
+func (n *JsonParseNode) getObjectValue(ctor absser.ParsableFactory, collector *errorCollector) (absser.Parsable, error) {
 	result, err := ctor(n)
 	if err != nil {
-		return nil, err
+		return nil, n.newParseError("", err)
+	}
+	result, err = n.resolveDerivedType(result)
+	if err != nil {
+		return nil, n.newParseError("", err)
 	}
 
 	_, isUntypedNode := result.(absser.UntypedNodeable)
@@ -282,6 +595,8 @@ func (n *JsonParseNode) GetObjectValue(ctor absser.ParsableFactory) (absser.Pars
 			return absser.NewUntypedInteger(*value), nil
 		case *int64:
 			return absser.NewUntypedLong(*value), nil
+		case *json.Number:
+			return n.rawToUntypedNodeable(value), nil
 		case nil:
 			return absser.NewUntypedNull(), nil
 		case map[string]interface{}:
@@ -290,7 +605,11 @@ func (n *JsonParseNode) GetObjectValue(ctor absser.ParsableFactory) (absser.Pars
 				var parsable absser.Parsable
 				if rawVal == nil {
 					parsable = absser.NewUntypedNull()
-				} else if jn, ok := rawVal.(*JsonParseNode); ok {
+				} else if _, ok := rawVal.(*JsonParseNode); ok {
+					jn, err := n.memberNode(key, rawVal)
+					if err != nil {
+						return nil, err
+					}
 					parsable, err = jn.GetObjectValue(absser.CreateUntypedNodeFromDiscriminatorValue)
 					if err != nil {
 						return nil, fmt.Errorf("cannot parse object value: %w", err)
@@ -299,12 +618,15 @@ func (n *JsonParseNode) GetObjectValue(ctor absser.ParsableFactory) (absser.Pars
 						parsable = absser.NewUntypedNull()
 					}
 				} else {
-					parsable = rawToUntypedNodeable(rawVal)
+					parsable = n.rawToUntypedNodeable(rawVal)
 				}
 				if property, ok := parsable.(absser.UntypedNodeable); ok {
 					properties[key] = property
 				}
 			}
+			if n.getOptions().losslessRoundTrip {
+				return NewOrderedUntypedObject(orderedKeys(n.memberKeys(), value), properties), nil
+			}
 			return absser.NewUntypedObject(properties), nil
 		case []interface{}:
 			collection := make([]absser.UntypedNodeable, len(value))
@@ -312,7 +634,11 @@ func (n *JsonParseNode) GetObjectValue(ctor absser.ParsableFactory) (absser.Pars
 				var parsable absser.Parsable
 				if rawElem == nil {
 					parsable = absser.NewUntypedNull()
-				} else if jn, ok := rawElem.(*JsonParseNode); ok {
+				} else if _, ok := rawElem.(*JsonParseNode); ok {
+					jn, err := n.elementNode(index, rawElem)
+					if err != nil {
+						return nil, err
+					}
 					parsable, err = jn.GetObjectValue(absser.CreateUntypedNodeFromDiscriminatorValue)
 					if err != nil {
 						return nil, fmt.Errorf("cannot parse object value: %w", err)
@@ -321,7 +647,7 @@ func (n *JsonParseNode) GetObjectValue(ctor absser.ParsableFactory) (absser.Pars
 						parsable = absser.NewUntypedNull()
 					}
 				} else {
-					parsable = rawToUntypedNodeable(rawElem)
+					parsable = n.rawToUntypedNodeable(rawElem)
 				}
 				if property, ok := parsable.(absser.UntypedNodeable); ok {
 					collection[index] = property
@@ -335,7 +661,7 @@ func (n *JsonParseNode) GetObjectValue(ctor absser.ParsableFactory) (absser.Pars
 
 	abstractions.InvokeParsableAction(n.GetOnBeforeAssignFieldValues(), result)
 	properties, ok := n.value.(map[string]interface{})
-	fields := result.GetFieldDeserializers()
+	fields := n.namedFieldDeserializers(result.GetFieldDeserializers())
 	if ok && len(properties) != 0 {
 		itemAsHolder, isHolder := result.(absser.AdditionalDataHolder)
 		var itemAdditionalData map[string]interface{}
@@ -347,11 +673,24 @@ func (n *JsonParseNode) GetObjectValue(ctor absser.ParsableFactory) (absser.Pars
 			}
 		}
 
+		roundTrip := n.getOptions().losslessRoundTrip
 		for key, rawValue := range properties {
 			field := fields[key]
-			if field == nil {
+			if field == nil && roundTrip {
+				if isHolder {
+					value, err := n.getUntypedMemberValue(key, rawValue)
+					if err != nil {
+						return nil, err
+					}
+					itemAdditionalData[key] = value
+				}
+			} else if field == nil {
 				if rawValue != nil && isHolder {
-					if jn, ok := rawValue.(*JsonParseNode); ok {
+					if _, ok := rawValue.(*JsonParseNode); ok {
+						jn, err := n.memberNode(key, rawValue)
+						if err != nil {
+							return nil, err
+						}
 						rv, err := jn.GetRawValue()
 						if err != nil {
 							return nil, err
@@ -359,40 +698,74 @@ func (n *JsonParseNode) GetObjectValue(ctor absser.ParsableFactory) (absser.Pars
 						itemAdditionalData[key] = rv
 					} else {
 						// Raw primitive stored directly – no need to create a JsonParseNode
-						itemAdditionalData[key] = rawValue
+						itemAdditionalData[key] = primitiveToRawValue(rawValue)
 					}
 				}
 			} else {
 				// Wrap raw values in a JsonParseNode on demand only for known fields
-				var childNode *JsonParseNode
-				if rawValue == nil {
-					childNode = nil
-				} else if jn, ok := rawValue.(*JsonParseNode); ok {
-					childNode = jn
-				} else {
-					childNode = &JsonParseNode{value: rawValue}
+				childNode, err := n.memberNode(key, rawValue)
+				if err != nil {
+					return nil, err
 				}
-				if childNode != nil {
-					err := childNode.SetOnBeforeAssignFieldValues(n.GetOnBeforeAssignFieldValues())
-					if err != nil {
-						return nil, err
+				err = field(childNode)
+				if err != nil {
+					if childNode == nil {
+						pointer := childPointer(n.pointer(), key)
+						err = newParseErrorAt(pointer, n.source, n.memberOffset(key), nil, "", err)
+					} else {
+						err = childNode.newParseError("", err)
 					}
-					err = childNode.SetOnAfterAssignFieldValues(n.GetOnAfterAssignFieldValues())
-					if err != nil {
+					if collector == nil {
 						return nil, err
 					}
-				}
-				err := field(childNode)
-				if err != nil {
-					return nil, err
+					collector.add(n, err)
 				}
 			}
 		}
+		if roundTrip && isHolder {
+			n.recordAdditionalDataOrder(itemAdditionalData, fields)
+		}
 	}
 	abstractions.InvokeParsableAction(n.GetOnAfterAssignFieldValues(), result)
 	return result, nil
 }
 
+// getUntypedMemberValue returns the untyped node holding a member of the object, nulls included.
+func (n *JsonParseNode) getUntypedMemberValue(key string, rawValue interface{}) (absser.UntypedNodeable, error) {
+	if rawValue == nil {
+		return absser.NewUntypedNull(), nil
+	}
+	if _, ok := rawValue.(*JsonParseNode); !ok {
+		return n.rawToUntypedNodeable(rawValue), nil
+	}
+	jn, err := n.memberNode(key, rawValue)
+	if err != nil {
+		return nil, err
+	}
+	parsable, err := jn.GetObjectValue(absser.CreateUntypedNodeFromDiscriminatorValue)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := parsable.(absser.UntypedNodeable)
+	if !ok {
+		return absser.NewUntypedNull(), nil
+	}
+	return value, nil
+}
+
+// recordAdditionalDataOrder records the order of the members of the object that were not read by a
+// field deserializer and were added to the additional data.
+func (n *JsonParseNode) recordAdditionalDataOrder(additionalData map[string]interface{}, fields map[string]func(absser.ParseNode) error) {
+	properties, _ := n.value.(map[string]interface{})
+	order := make([]string, 0, len(properties))
+	for _, key := range orderedKeys(n.memberKeys(), properties) {
+		if fields[key] == nil {
+			order = append(order, key)
+		}
+	}
+	recordAdditionalDataOrder(additionalData, order)
+}
+
 // GetCollectionOfObjectValues returns the collection of Parsable values from the node.
 func (n *JsonParseNode) GetCollectionOfObjectValues(ctor absser.ParsableFactory) ([]absser.Parsable, error) {
 	if isNil(n) || isNil(n.value) {
@@ -401,9 +774,25 @@ func (n *JsonParseNode) GetCollectionOfObjectValues(ctor absser.ParsableFactory)
 	if ctor == nil {
 		return nil, errors.New("ctor is nil")
 	}
+	collector, owner := n.startCollecting()
+	if collector == nil {
+		return n.getCollectionOfObjectValues(ctor, nil)
+	}
+	result, err := n.getCollectionOfObjectValues(ctor, collector)
+	if err != nil {
+		collector.add(n, err)
+	}
+	if owner {
+		return result, collector.finish()
+	}
+	return result, nil
+}
+
+// getCollectionOfObjectValues reads the Parsable values of the node, reporting the failures of elements to the collector when one is set.
+func (n *JsonParseNode) getCollectionOfObjectValues(ctor absser.ParsableFactory, collector *errorCollector) ([]absser.Parsable, error) {
 	nodes, ok := n.value.([]interface{})
 	if !ok {
-		return nil, errors.New("value is not a collection")
+		return nil, n.newParseError("array", errors.New("value is not a collection"))
 	}
 	result := make([]absser.Parsable, len(nodes))
 	for i, rawElem := range nodes {
@@ -411,9 +800,17 @@ func (n *JsonParseNode) GetCollectionOfObjectValues(ctor absser.ParsableFactory)
 			result[i] = nil
 			continue
 		}
-		jn, ok := rawElem.(*JsonParseNode)
-		if !ok {
-			return nil, errors.New("collection element is not a parse node")
+		if _, ok := rawElem.(*JsonParseNode); !ok {
+			err := n.elementParseError(i, rawElem, "object", errors.New("collection element is not a parse node"))
+			if collector == nil {
+				return nil, err
+			}
+			collector.add(n, err)
+			continue
+		}
+		jn, err := n.elementNode(i, rawElem)
+		if err != nil {
+			return nil, err
 		}
 		val, err := jn.GetObjectValue(ctor)
 		if err != nil {
@@ -434,7 +831,7 @@ func (n *JsonParseNode) GetCollectionOfPrimitiveValues(targetType string) ([]int
 	}
 	nodes, ok := n.value.([]interface{})
 	if !ok {
-		return nil, errors.New("value is not a collection")
+		return nil, n.newParseError("array", errors.New("value is not a collection"))
 	}
 	result := make([]interface{}, len(nodes))
 	for i, rawElem := range nodes {
@@ -442,8 +839,12 @@ func (n *JsonParseNode) GetCollectionOfPrimitiveValues(targetType string) ([]int
 			result[i] = nil
 			continue
 		}
-		if jn, ok := rawElem.(*JsonParseNode); ok {
+		if _, ok := rawElem.(*JsonParseNode); ok {
 			// Complex node (e.g. nested array/object used as a primitive collection element)
+			jn, err := n.elementNode(i, rawElem)
+			if err != nil {
+				return nil, err
+			}
 			val, err := jn.getPrimitiveValue(targetType)
 			if err != nil {
 				return nil, err
@@ -452,9 +853,12 @@ func (n *JsonParseNode) GetCollectionOfPrimitiveValues(targetType string) ([]int
 		} else {
 			// Raw primitive value stored without a JsonParseNode wrapper – convert directly
 			// to avoid allocating an intermediate node.
-			val, err := rawToPrimitiveValue(rawElem, targetType)
+			val, err := rawToPrimitiveValue(rawElem, targetType, n.options)
 			if err != nil {
-				return nil, err
+				if isUnsupportedTargetType(err) {
+					return nil, err
+				}
+				return nil, n.elementParseError(i, rawElem, targetType, err)
 			}
 			result[i] = val
 		}
@@ -496,14 +900,28 @@ func (n *JsonParseNode) getPrimitiveValue(targetType string) (interface{}, error
 	case "base64":
 		return n.GetByteArrayValue()
 	default:
-		return nil, fmt.Errorf("targetType %s is not supported", targetType)
+		return nil, unsupportedTargetTypeError(targetType)
 	}
 }
 
+// unsupportedTargetTypeError is returned when a collection of primitives is read as an unknown type.
+// It reports a faulty call rather than a faulty payload and is therefore not a ParseError.
+type unsupportedTargetTypeError string
+
+func (e unsupportedTargetTypeError) Error() string {
+	return fmt.Sprintf("targetType %s is not supported", string(e))
+}
+
+// isUnsupportedTargetType returns whether the error reports an unknown primitive type.
+func isUnsupportedTargetType(err error) bool {
+	var target unsupportedTargetTypeError
+	return errors.As(err, &target)
+}
+
 // rawToPrimitiveValue converts a raw primitive value (stored without a JsonParseNode wrapper)
 // to the requested target type. This avoids allocating an intermediate JsonParseNode when
 // processing collections of primitive values.
-func rawToPrimitiveValue(rawValue interface{}, targetType string) (interface{}, error) {
+func rawToPrimitiveValue(rawValue interface{}, targetType string, options *parseNodeOptions) (interface{}, error) {
 	switch targetType {
 	case "string":
 		if sp, ok := rawValue.(*string); ok {
@@ -563,7 +981,7 @@ func rawToPrimitiveValue(rawValue interface{}, targetType string) (interface{},
 		return &val, nil
 	case "time":
 		// For time/date types, the raw value should be a *string; delegate to a temporary node
-		tmpNode := &JsonParseNode{value: rawValue}
+		tmpNode := &JsonParseNode{value: rawValue, options: options}
 		return tmpNode.GetTimeValue()
 	case "timeonly":
 		tmpNode := &JsonParseNode{value: rawValue}
@@ -578,14 +996,15 @@ func rawToPrimitiveValue(rawValue interface{}, targetType string) (interface{},
 		tmpNode := &JsonParseNode{value: rawValue}
 		return tmpNode.GetUUIDValue()
 	case "base64":
-		tmpNode := &JsonParseNode{value: rawValue}
+		tmpNode := &JsonParseNode{value: rawValue, options: options}
 		return tmpNode.GetByteArrayValue()
 	default:
-		return nil, fmt.Errorf("targetType %s is not supported", targetType)
+		return nil, unsupportedTargetTypeError(targetType)
 	}
 }
 
-// GetCollectionOfEnumValues returns the collection of Enum values from the node.
+// GetCollectionOfEnumValues returns the collection of Enum values from the node, flags enum values being read
+// as GetEnumValue does.
 func (n *JsonParseNode) GetCollectionOfEnumValues(parser absser.EnumFactory) ([]interface{}, error) {
 	if isNil(n) || isNil(n.value) {
 		return nil, nil
@@ -595,7 +1014,7 @@ func (n *JsonParseNode) GetCollectionOfEnumValues(parser absser.EnumFactory) ([]
 	}
 	nodes, ok := n.value.([]interface{})
 	if !ok {
-		return nil, errors.New("value is not a collection")
+		return nil, n.newParseError("array", errors.New("value is not a collection"))
 	}
 	result := make([]interface{}, len(nodes))
 	for i, rawElem := range nodes {
@@ -604,8 +1023,11 @@ func (n *JsonParseNode) GetCollectionOfEnumValues(parser absser.EnumFactory) ([]
 			continue
 		}
 		var strVal *string
-		if jn, ok := rawElem.(*JsonParseNode); ok {
-			var err error
+		if _, ok := rawElem.(*JsonParseNode); ok {
+			jn, err := n.elementNode(i, rawElem)
+			if err != nil {
+				return nil, err
+			}
 			strVal, err = jn.GetStringValue()
 			if err != nil {
 				return nil, err
@@ -613,15 +1035,15 @@ func (n *JsonParseNode) GetCollectionOfEnumValues(parser absser.EnumFactory) ([]
 		} else if sp, ok := rawElem.(*string); ok {
 			strVal = sp
 		} else {
-			return nil, fmt.Errorf("enum collection element has unexpected type %T", rawElem)
+			return nil, n.elementParseError(i, rawElem, "enum", fmt.Errorf("enum collection element has unexpected type %T", rawElem))
 		}
 		if strVal == nil {
 			result[i] = nil
 			continue
 		}
-		val, err := parser(*strVal)
+		val, err := parseEnum(parser, *strVal, n.getOptions())
 		if err != nil {
-			return nil, err
+			return nil, n.elementParseError(i, rawElem, "enum", err)
 		}
 		result[i] = val
 	}
@@ -636,7 +1058,7 @@ func (n *JsonParseNode) GetStringValue() (*string, error) {
 
 	val, ok := n.value.(*string)
 	if !ok {
-		return nil, fmt.Errorf("type '%T' is not compatible with type string", n.value)
+		return nil, n.newParseError("string", fmt.Errorf("type '%T' is not compatible with type string", n.value))
 	}
 	return val, nil
 }
@@ -649,7 +1071,7 @@ func (n *JsonParseNode) GetBoolValue() (*bool, error) {
 
 	val, ok := n.value.(*bool)
 	if !ok {
-		return nil, fmt.Errorf("type '%T' is not compatible with type bool", n.value)
+		return nil, n.newParseError("bool", fmt.Errorf("type '%T' is not compatible with type bool", n.value))
 	}
 	return val, nil
 }
@@ -662,7 +1084,7 @@ func (n *JsonParseNode) GetInt8Value() (*int8, error) {
 	var val int8
 
 	if err := as(n.value, &val); err != nil {
-		return nil, err
+		return nil, n.newParseError("int8", err)
 	}
 
 	return &val, nil
@@ -676,7 +1098,7 @@ func (n *JsonParseNode) GetByteValue() (*byte, error) {
 	var val byte
 
 	if err := as(n.value, &val); err != nil {
-		return nil, err
+		return nil, n.newParseError("byte", err)
 	}
 
 	return &val, nil
@@ -690,7 +1112,7 @@ func (n *JsonParseNode) GetFloat32Value() (*float32, error) {
 	var val float32
 
 	if err := as(n.value, &val); err != nil {
-		return nil, err
+		return nil, n.newParseError("float32", err)
 	}
 
 	return &val, nil
@@ -704,7 +1126,7 @@ func (n *JsonParseNode) GetFloat64Value() (*float64, error) {
 	var val float64
 
 	if err := as(n.value, &val); err != nil {
-		return nil, err
+		return nil, n.newParseError("float64", err)
 	}
 
 	return &val, nil
@@ -718,7 +1140,7 @@ func (n *JsonParseNode) GetInt32Value() (*int32, error) {
 	var val int32
 
 	if err := as(n.value, &val); err != nil {
-		return nil, err
+		return nil, n.newParseError("int32", err)
 	}
 
 	return &val, nil
@@ -732,31 +1154,60 @@ func (n *JsonParseNode) GetInt64Value() (*int64, error) {
 	var val int64
 
 	if err := as(n.value, &val); err != nil {
-		return nil, err
+		return nil, n.newParseError("int64", err)
 	}
 
 	return &val, nil
 }
 
-// GetTimeValue returns a Time value from the nodes.
-func (n *JsonParseNode) GetTimeValue() (*time.Time, error) {
+// GetBigIntValue returns an arbitrary-precision integer value from the nodes.
+func (n *JsonParseNode) GetBigIntValue() (*big.Int, error) {
 	if isNil(n) || isNil(n.value) {
 		return nil, nil
 	}
-	v, err := n.GetStringValue()
+	s, ok := numberText(n.value)
+	if !ok {
+		return nil, n.newParseError("big.Int", fmt.Errorf("type '%T' is not compatible with type big.Int", n.value))
+	}
+	if val, ok := new(big.Int).SetString(s, 10); ok {
+		return val, nil
+	}
+	// integral values written with a fraction or an exponent, e.g. 1.0 or 1e3
+	val, err := parseIntegralNumber(s)
 	if err != nil {
-		return nil, err
+		return nil, n.newParseError("big.Int", err)
 	}
-	if v == nil {
+	return val, nil
+}
+
+// GetBigFloatValue returns an arbitrary-precision floating-point value from the nodes.
+// The precision is chosen from the length of the number so every digit of the payload is kept.
+func (n *JsonParseNode) GetBigFloatValue() (*big.Float, error) {
+	if isNil(n) || isNil(n.value) {
 		return nil, nil
 	}
+	s, ok := numberText(n.value)
+	if !ok {
+		return nil, n.newParseError("big.Float", fmt.Errorf("type '%T' is not compatible with type big.Float", n.value))
+	}
+	val, err := parseBigFloat(s)
+	if err != nil {
+		return nil, n.newParseError("big.Float", fmt.Errorf("value '%s' is not compatible with type big.Float", s))
+	}
+	return val, nil
+}
 
-	// if string does not have timezone information, add local timezone
-	if len(*v) == 19 {
-		*v = *v + time.Now().Format("-07:00")
+// GetTimeValue returns a Time value from the nodes. The accepted layouts and the location of values without
+// an offset are set with WithTimeLayouts and WithTimeLocation.
+func (n *JsonParseNode) GetTimeValue() (*time.Time, error) {
+	if isNil(n) || isNil(n.value) {
+		return nil, nil
 	}
-	parsed, err := time.Parse(time.RFC3339, *v)
-	return &parsed, err
+	parsed, err := parseTime(n.value, n.getOptions())
+	if err != nil {
+		return nil, n.newParseError("time.Time", err)
+	}
+	return parsed, nil
 }
 
 // GetISODurationValue returns a ISODuration value from the nodes.
@@ -771,7 +1222,11 @@ func (n *JsonParseNode) GetISODurationValue() (*absser.ISODuration, error) {
 	if v == nil {
 		return nil, nil
 	}
-	return absser.ParseISODuration(*v)
+	val, err := absser.ParseISODuration(*v)
+	if err != nil {
+		return nil, n.newParseError("ISODuration", err)
+	}
+	return val, nil
 }
 
 // GetTimeOnlyValue returns a TimeOnly value from the nodes.
@@ -786,7 +1241,11 @@ func (n *JsonParseNode) GetTimeOnlyValue() (*absser.TimeOnly, error) {
 	if v == nil {
 		return nil, nil
 	}
-	return absser.ParseTimeOnly(*v)
+	val, err := absser.ParseTimeOnly(*v)
+	if err != nil {
+		return nil, n.newParseError("TimeOnly", err)
+	}
+	return val, nil
 }
 
 // GetDateOnlyValue returns a DateOnly value from the nodes.
@@ -801,7 +1260,11 @@ func (n *JsonParseNode) GetDateOnlyValue() (*absser.DateOnly, error) {
 	if v == nil {
 		return nil, nil
 	}
-	return absser.ParseDateOnly(*v)
+	val, err := absser.ParseDateOnly(*v)
+	if err != nil {
+		return nil, n.newParseError("DateOnly", err)
+	}
+	return val, nil
 }
 
 // GetUUIDValue returns a UUID value from the nodes.
@@ -817,10 +1280,14 @@ func (n *JsonParseNode) GetUUIDValue() (*uuid.UUID, error) {
 		return nil, nil
 	}
 	parsed, err := uuid.Parse(*v)
-	return &parsed, err
+	if err != nil {
+		return nil, n.newParseError("uuid.UUID", err)
+	}
+	return &parsed, nil
 }
 
-// GetEnumValue returns a Enum value from the nodes.
+// GetEnumValue returns a Enum value from the nodes. Values the factory does not recognize that hold several
+// members, separated as set with WithEnumSeparator, are read member by member and the members combined.
 func (n *JsonParseNode) GetEnumValue(parser absser.EnumFactory) (interface{}, error) {
 	if isNil(n) || isNil(n.value) {
 		return nil, nil
@@ -835,22 +1302,23 @@ func (n *JsonParseNode) GetEnumValue(parser absser.EnumFactory) (interface{}, er
 	if s == nil {
 		return nil, nil
 	}
-	return parser(*s)
+	val, err := parseEnum(parser, *s, n.getOptions())
+	if err != nil {
+		return nil, n.newParseError("enum", err)
+	}
+	return val, nil
 }
 
-// GetByteArrayValue returns a ByteArray value from the nodes.
+// GetByteArrayValue returns a ByteArray value from the nodes, decoded as set with WithBinaryEncoding.
 func (n *JsonParseNode) GetByteArrayValue() ([]byte, error) {
 	if isNil(n) || isNil(n.value) {
 		return nil, nil
 	}
-	s, err := n.GetStringValue()
+	val, err := decodeBinary(n.value, n.getOptions())
 	if err != nil {
-		return nil, err
+		return nil, n.newParseError("base64", err)
 	}
-	if s == nil {
-		return nil, nil
-	}
-	return base64.StdEncoding.DecodeString(*s)
+	return val, nil
 }
 
 // GetRawValue returns a ByteArray value from the nodes.
@@ -868,7 +1336,11 @@ func (n *JsonParseNode) GetRawValue() (interface{}, error) {
 				result[i] = nil
 				continue
 			}
-			if jn, ok := x.(*JsonParseNode); ok {
+			if _, ok := x.(*JsonParseNode); ok {
+				jn, err := n.elementNode(i, x)
+				if err != nil {
+					return nil, err
+				}
 				val, err := jn.GetRawValue()
 				if err != nil {
 					return nil, err
@@ -876,7 +1348,7 @@ func (n *JsonParseNode) GetRawValue() (interface{}, error) {
 				result[i] = val
 			} else {
 				// Raw primitive – return as-is
-				result[i] = x
+				result[i] = primitiveToRawValue(x)
 			}
 		}
 		return result, nil
@@ -887,7 +1359,11 @@ func (n *JsonParseNode) GetRawValue() (interface{}, error) {
 				m[key] = nil
 				continue
 			}
-			if jn, ok := element.(*JsonParseNode); ok {
+			if _, ok := element.(*JsonParseNode); ok {
+				jn, err := n.memberNode(key, element)
+				if err != nil {
+					return nil, err
+				}
 				elementVal, err := jn.GetRawValue()
 				if err != nil {
 					return nil, err
@@ -895,12 +1371,12 @@ func (n *JsonParseNode) GetRawValue() (interface{}, error) {
 				m[key] = elementVal
 			} else {
 				// Raw primitive – return as-is
-				m[key] = element
+				m[key] = primitiveToRawValue(element)
 			}
 		}
 		return m, nil
 	default:
-		return n.value, nil
+		return primitiveToRawValue(n.value), nil
 	}
 }
 
@@ -922,6 +1398,16 @@ func (n *JsonParseNode) SetOnAfterAssignFieldValues(action absser.ParsableAction
 	return nil
 }
 
+// rawToUntypedNodeable converts a raw primitive value of the node to an absser.UntypedNodeable.
+// In round-trip mode numbers are held by an UntypedNode as their json.Number text so they are written
+// back as they were read.
+func (n *JsonParseNode) rawToUntypedNodeable(v interface{}) absser.UntypedNodeable {
+	if number, ok := v.(*json.Number); ok && n.getOptions().losslessRoundTrip {
+		return absser.NewUntypedNode(*number)
+	}
+	return rawToUntypedNodeable(v)
+}
+
 // rawToUntypedNodeable converts a raw primitive value (stored without a JsonParseNode wrapper)
 // to an absser.UntypedNodeable. Used when constructing untyped node trees from optimised
 // (allocation-reduced) parse trees.
@@ -943,7 +1429,47 @@ func rawToUntypedNodeable(v interface{}) absser.UntypedNodeable {
 		return absser.NewUntypedInteger(int32(*rv))
 	case *byte:
 		return absser.NewUntypedInteger(int32(*rv))
+	case *json.Number:
+		return rawToUntypedNodeable(numberToRawValue(*rv))
 	default:
 		return absser.NewUntypedNode(v)
 	}
 }
+
+// maxExactFloatInteger is the largest integer magnitude a float64 represents without loss (2^53).
+const maxExactFloatInteger = 1 << 53
+
+// primitiveToRawValue converts a primitive value stored in the tree to the value exposed through
+// GetRawValue and additional data. Only numbers need converting, everything else is returned as-is.
+func primitiveToRawValue(v interface{}) interface{} {
+	if number, ok := v.(*json.Number); ok {
+		return numberToRawValue(*number)
+	}
+	return v
+}
+
+// numberToRawValue converts a number lexeme to a Go value without losing precision. Numbers a float64
+// holds exactly stay *float64 as they always have, larger integers become *int64 or *big.Int and
+// decimals outside of the float64 range become *big.Float.
+func numberToRawValue(number json.Number) interface{} {
+	s := number.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			if i >= -maxExactFloatInteger && i <= maxExactFloatInteger {
+				f := float64(i)
+				return &f
+			}
+			return &i
+		}
+		if i, ok := new(big.Int).SetString(s, 10); ok {
+			return i
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return &f
+	}
+	if f, err := parseBigFloat(s); err == nil {
+		return f
+	}
+	return &number
+}
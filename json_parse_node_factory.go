@@ -2,17 +2,21 @@ package jsonserialization
 
 import (
 	"errors"
+	"io"
 
 	absser "github.com/microsoft/kiota-abstractions-go/serialization"
 )
 
 // JsonParseNodeFactory is a ParseNodeFactory implementation for JSON
 type JsonParseNodeFactory struct {
+	options []JsonParseNodeOption
 }
 
-// NewJsonParseNodeFactory creates a new JsonParseNodeFactory
-func NewJsonParseNodeFactory() *JsonParseNodeFactory {
-	return &JsonParseNodeFactory{}
+// NewJsonParseNodeFactory creates a new JsonParseNodeFactory, the options apply to every parse node it creates.
+func NewJsonParseNodeFactory(opts ...JsonParseNodeOption) *JsonParseNodeFactory {
+	return &JsonParseNodeFactory{
+		options: opts,
+	}
 }
 
 // GetValidContentType returns the content type this factory's parse nodes can deserialize.
@@ -22,14 +26,29 @@ func (f *JsonParseNodeFactory) GetValidContentType() (string, error) {
 
 // GetRootParseNode return a new ParseNode instance that is the root of the content
 func (f *JsonParseNodeFactory) GetRootParseNode(contentType string, content []byte) (absser.ParseNode, error) {
+	if err := f.validateContentType(contentType); err != nil {
+		return nil, err
+	}
+	return NewJsonParseNode(content, f.options...)
+}
+
+// GetRootParseNodeFromReader return a new ParseNode instance that is the root of the content read from the reader.
+// The content is decoded as it is read, which avoids holding the whole payload in memory before parsing it.
+func (f *JsonParseNodeFactory) GetRootParseNodeFromReader(contentType string, content io.Reader) (absser.ParseNode, error) {
+	if err := f.validateContentType(contentType); err != nil {
+		return nil, err
+	}
+	return NewJsonParseNodeFromReader(content, f.options...)
+}
+
+func (f *JsonParseNodeFactory) validateContentType(contentType string) error {
 	validType, err := f.GetValidContentType()
 	if err != nil {
-		return nil, err
+		return err
 	} else if contentType == "" {
-		return nil, errors.New("contentType is empty")
+		return errors.New("contentType is empty")
 	} else if contentType != validType {
-		return nil, errors.New("contentType is not valid")
-	} else {
-		return NewJsonParseNode(content)
+		return errors.New("contentType is not valid")
 	}
+	return nil
 }
@@ -1,9 +1,13 @@
 package jsonserialization
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/microsoft/kiota-serialization-json-go/internal"
 	assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	absser "github.com/microsoft/kiota-abstractions-go/serialization"
 )
@@ -21,3 +25,47 @@ func TestInvalidContentShouldFail(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, parseNode)
 }
+
+func TestGetRootParseNodeFromReader(t *testing.T) {
+	instance := NewJsonParseNodeFactory()
+	parseNode, err := instance.GetRootParseNodeFromReader("application/json", strings.NewReader(`{"id":"opaque"}`))
+	assert.NoError(t, err)
+	child, err := parseNode.GetChildNode("id")
+	assert.NoError(t, err)
+	value, err := child.GetStringValue()
+	assert.NoError(t, err)
+	assert.Equal(t, "opaque", *value)
+
+	_, err = instance.GetRootParseNodeFromReader("application/xml", strings.NewReader(`{}`))
+	assert.Error(t, err)
+}
+
+func TestJsonParseNodeFactoryAppliesOptions(t *testing.T) {
+	instance := NewJsonParseNodeFactory(WithCollectAllErrors())
+	node, err := instance.GetRootParseNode("application/json", []byte(`{"id": 1, "birthDay": "not a date"}`))
+	require.NoError(t, err)
+
+	_, err = node.GetObjectValue(internal.CreateTestEntityFromDiscriminator)
+	var aggregate *AggregateParseError
+	require.True(t, errors.As(err, &aggregate))
+	assert.Len(t, aggregate.Errors, 2)
+}
+
+func TestJsonParseNodeFactoryAppliesNamingPolicy(t *testing.T) {
+	instance := NewJsonParseNodeFactory(WithNamingPolicy(KebabCaseNamingPolicy))
+	node, err := instance.GetRootParseNode("application/json", []byte(`{"id": "1", "office-location": "Montreal"}`))
+	require.NoError(t, err)
+
+	result, err := node.GetObjectValue(internal.CreateTestEntityFromDiscriminator)
+	require.NoError(t, err)
+	assert.Equal(t, "Montreal", *result.(*internal.TestEntity).GetOfficeLocation())
+}
+
+func TestJsonParseNodeFactoryDuplicateKeyPolicy(t *testing.T) {
+	instance := NewJsonParseNodeFactory(WithDuplicateKeyPolicy(DuplicateKeyError))
+	_, err := instance.GetRootParseNode("application/json", []byte(`{"id": "1", "id": "2"}`))
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+
+	_, err = instance.GetRootParseNodeFromReader("application/json", strings.NewReader(`{"id": "1", "id": "2"}`))
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+}
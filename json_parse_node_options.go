@@ -0,0 +1,132 @@
+package jsonserialization
+
+import (
+	"errors"
+	"time"
+)
+
+// JsonParseNodeOption configures how JSON payloads are read. Options are passed to NewJsonParseNode,
+// NewJsonParseNodeFromReader or NewJsonParseNodeFactory and apply to every node of the parsed tree.
+type JsonParseNodeOption interface {
+	applyToParseNode(options *parseNodeOptions)
+}
+
+// JsonOption configures both how payloads are read and how they are written, so both sides stay
+// consistent. It is accepted wherever a JsonParseNodeOption or a JsonSerializationWriterOption is.
+type JsonOption interface {
+	JsonParseNodeOption
+	JsonSerializationWriterOption
+}
+
+// parseNodeOptions holds the settings shared by the nodes of a parse tree.
+type parseNodeOptions struct {
+	collectAllErrors   bool
+	duplicateKeyPolicy DuplicateKeyPolicy
+	losslessRoundTrip  bool
+	limits             Limits
+	lazyLoading        bool
+	timeLayouts        []string
+	timeLocation       *time.Location
+	binaryEncoding     BinaryEncoding
+	// lenientBinaryDecoding is set by WithLenientBinaryDecoding.
+	lenientBinaryDecoding bool
+	enumSeparator         string
+	caseInsensitiveEnums  bool
+	discriminators        *DiscriminatorRegistry
+	namingPolicy          *namingPolicy
+}
+
+// defaultParseNodeOptions is used by nodes that were not created with any option.
+var defaultParseNodeOptions = parseNodeOptions{}
+
+type parseNodeOptionFunc func(options *parseNodeOptions)
+
+func (f parseNodeOptionFunc) applyToParseNode(options *parseNodeOptions) {
+	f(options)
+}
+
+// newParseNodeOptions returns the settings resulting from the options, nil when there is none.
+func newParseNodeOptions(opts []JsonParseNodeOption) *parseNodeOptions {
+	if len(opts) == 0 {
+		return nil
+	}
+	options := defaultParseNodeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyToParseNode(&options)
+		}
+	}
+	return &options
+}
+
+// WithCollectAllErrors makes GetObjectValue and GetCollectionOfObjectValues attempt every field instead
+// of stopping at the first failure. The failures are returned as a single *AggregateParseError along
+// with the partially populated result.
+func WithCollectAllErrors() JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.collectAllErrors = true
+	})
+}
+
+// WithLosslessRoundTrip keeps what is needed to write the unknown members of a payload back as they
+// were read. Untyped objects are read as *OrderedUntypedObject, numbers of untyped nodes are held by an
+// *absser.UntypedNode as their json.Number text, and additional data holds untyped nodes, nulls included.
+// The order of the unknown members is recorded for the additional data of any AdditionalDataHolder, and
+// WriteAdditionalData writes them back in that order while the values read remain in the map.
+func WithLosslessRoundTrip() JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.losslessRoundTrip = true
+	})
+}
+
+// WithLazyLoading keeps the nested objects and arrays of the payload as ranges of the content until they
+// are reached through GetChildNode, GetObjectValue, GetRawValue or any other getter, so subtrees that are
+// never read are only validated. The content must not be modified while the tree is in use.
+// Duplicate keys and the member and element count limits of a subtree are checked when it is loaded.
+// The option has no effect on NewJsonParseNodeFromReader, which does not keep the content it reads.
+func WithLazyLoading() JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.lazyLoading = true
+	})
+}
+
+// DuplicateKeyPolicy decides how an object holding the same key more than once is loaded.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyLastWins keeps the value of the last occurrence of the key, as encoding/json does.
+	DuplicateKeyLastWins DuplicateKeyPolicy = iota
+	// DuplicateKeyFirstWins keeps the value of the first occurrence of the key.
+	DuplicateKeyFirstWins
+	// DuplicateKeyError rejects the payload with a *ParseError wrapping ErrDuplicateKey.
+	DuplicateKeyError
+	// DuplicateKeyCollect gathers the values of all the occurrences of the key into an array.
+	DuplicateKeyCollect
+)
+
+// ErrDuplicateKey is reported when an object holds the same key more than once under DuplicateKeyError.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// WithDuplicateKeyPolicy sets how objects holding the same key more than once are loaded.
+// The default is DuplicateKeyLastWins.
+func WithDuplicateKeyPolicy(policy DuplicateKeyPolicy) JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.duplicateKeyPolicy = policy
+	})
+}
+
+// getOptions returns the settings of the node.
+func (n *JsonParseNode) getOptions() *parseNodeOptions {
+	if n.options == nil {
+		return &defaultParseNodeOptions
+	}
+	return n.options
+}
+
+// SetCollectAllErrors enables or disables the collect-all-errors mode for the node and the nodes
+// read from it afterwards, see WithCollectAllErrors.
+func (n *JsonParseNode) SetCollectAllErrors(enabled bool) {
+	options := *n.getOptions()
+	options.collectAllErrors = enabled
+	n.options = &options
+}
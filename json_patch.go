@@ -0,0 +1,659 @@
+package jsonserialization
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"strconv"
+	"strings"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// Operations of RFC 6902 JSON Patch documents.
+const (
+	JsonPatchAdd     = "add"
+	JsonPatchRemove  = "remove"
+	JsonPatchReplace = "replace"
+	JsonPatchMove    = "move"
+	JsonPatchCopy    = "copy"
+	JsonPatchTest    = "test"
+)
+
+// JsonPatchOperation is an operation of an RFC 6902 JSON Patch document.
+type JsonPatchOperation struct {
+	// Op is the operation, one of the JsonPatch constants.
+	Op string
+	// Path is the JSON Pointer of the value the operation applies to.
+	Path string
+	// From is the JSON Pointer of the value moved or copied.
+	From string
+	// Value is the value added, replaced or tested.
+	Value absser.UntypedNodeable
+}
+
+// JsonPatch is an RFC 6902 JSON Patch document, its operations being applied in order.
+type JsonPatch []JsonPatchOperation
+
+// JsonPatchError is returned when an operation of a JSON Patch document cannot be applied.
+type JsonPatchError struct {
+	// Index is the index of the operation in the document.
+	Index int
+	// Op is the operation.
+	Op string
+	// Path is the JSON Pointer the operation applies to.
+	Path string
+	// Err is the reason the operation failed.
+	Err error
+}
+
+// Error returns the description of the failure.
+func (e *JsonPatchError) Error() string {
+	return fmt.Sprintf("operation %d (%s %q) failed: %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+// Unwrap returns the reason the operation failed.
+func (e *JsonPatchError) Unwrap() error {
+	return e.Err
+}
+
+// ParseJsonPatch reads a JSON Patch document.
+func ParseJsonPatch(content []byte) (JsonPatch, error) {
+	parseNode, err := NewJsonParseNode(content, WithLosslessRoundTrip())
+	if err != nil {
+		return nil, err
+	}
+	elements, ok := parseNode.value.([]interface{})
+	if !ok {
+		return nil, parseNode.newParseError("array", errors.New("a JSON Patch document is an array of operations"))
+	}
+	patch := make(JsonPatch, 0, len(elements))
+	for index, rawValue := range elements {
+		node, err := parseNode.childOrNull("", index, rawValue)
+		if err != nil {
+			return nil, err
+		}
+		operation, err := node.getJsonPatchOperation()
+		if err != nil {
+			return nil, err
+		}
+		patch = append(patch, operation)
+	}
+	return patch, nil
+}
+
+// getJsonPatchOperation reads an operation of a JSON Patch document.
+func (n *JsonParseNode) getJsonPatchOperation() (JsonPatchOperation, error) {
+	var operation JsonPatchOperation
+	members, ok := n.value.(map[string]interface{})
+	if !ok {
+		return operation, n.newParseError("object", errors.New("an operation is an object"))
+	}
+	readString := func(key string, required bool) (string, error) {
+		rawValue, ok := members[key]
+		if !ok {
+			if required {
+				return "", n.newParseError("", fmt.Errorf("the operation has no %q member", key))
+			}
+			return "", nil
+		}
+		child, err := n.childOrNull(key, -1, rawValue)
+		if err != nil {
+			return "", err
+		}
+		value, ok := child.value.(*string)
+		if !ok {
+			return "", child.newParseError("string", fmt.Errorf("the %q member is not a string", key))
+		}
+		return *value, nil
+	}
+	var err error
+	if operation.Op, err = readString("op", true); err != nil {
+		return operation, err
+	}
+	if operation.Path, err = readString("path", true); err != nil {
+		return operation, err
+	}
+	switch operation.Op {
+	case JsonPatchMove, JsonPatchCopy:
+		operation.From, err = readString("from", true)
+		return operation, err
+	case JsonPatchAdd, JsonPatchReplace, JsonPatchTest:
+		rawValue, ok := members["value"]
+		if !ok {
+			return operation, n.newParseError("", errors.New(`the operation has no "value" member`))
+		}
+		child, err := n.childOrNull("value", -1, rawValue)
+		if err != nil {
+			return operation, err
+		}
+		operation.Value, err = child.getUntypedValue()
+		return operation, err
+	case JsonPatchRemove:
+		return operation, nil
+	default:
+		return operation, n.newParseError("", fmt.Errorf("unknown operation %q", operation.Op))
+	}
+}
+
+// getUntypedValue returns the untyped node of the value of the node, nulls included.
+func (n *JsonParseNode) getUntypedValue() (absser.UntypedNodeable, error) {
+	if n.value == nil {
+		return absser.NewUntypedNull(), nil
+	}
+	value, err := n.GetObjectValue(absser.CreateUntypedNodeFromDiscriminatorValue)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := value.(absser.UntypedNodeable)
+	if !ok {
+		return absser.NewUntypedNull(), nil
+	}
+	return node, nil
+}
+
+// ApplyJsonPatch applies the JSON Patch document to the target JSON document and returns the resulting
+// document. Members keep their order and numbers are written back as they were read.
+func ApplyJsonPatch(target []byte, patch []byte) ([]byte, error) {
+	operations, err := ParseJsonPatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	targetNode, err := parseUntypedJson(target)
+	if err != nil {
+		return nil, err
+	}
+	result, err := operations.Apply(targetNode)
+	if err != nil {
+		return nil, err
+	}
+	return writeUntypedJson(result)
+}
+
+// ApplyToNode applies the operations to the value of the parse node and returns the resulting value.
+func (p JsonPatch) ApplyToNode(target *JsonParseNode) (absser.UntypedNodeable, error) {
+	var targetNode absser.UntypedNodeable = absser.NewUntypedNull()
+	if !isNil(target) {
+		var err error
+		if targetNode, err = target.getUntypedValue(); err != nil {
+			return nil, err
+		}
+	}
+	return p.Apply(targetNode)
+}
+
+// Apply applies the operations to the target in order and returns the resulting value. Operations are
+// atomic: the target is never modified, and no result is returned when an operation fails.
+func (p JsonPatch) Apply(target absser.UntypedNodeable) (absser.UntypedNodeable, error) {
+	document := newPatchDocument(target)
+	for index, operation := range p {
+		if err := document.apply(operation); err != nil {
+			return nil, &JsonPatchError{Index: index, Op: operation.Op, Path: operation.Path, Err: err}
+		}
+	}
+	return document.root.toUntyped(), nil
+}
+
+// GetSerializedContent returns the JSON Patch document.
+func (p JsonPatch) GetSerializedContent() ([]byte, error) {
+	operations := make([]absser.UntypedNodeable, len(p))
+	for i, operation := range p {
+		keys := []string{"op", "path"}
+		members := map[string]absser.UntypedNodeable{
+			"op":   absser.NewUntypedString(operation.Op),
+			"path": absser.NewUntypedString(operation.Path),
+		}
+		switch operation.Op {
+		case JsonPatchMove, JsonPatchCopy:
+			keys = append(keys, "from")
+			members["from"] = absser.NewUntypedString(operation.From)
+		case JsonPatchAdd, JsonPatchReplace, JsonPatchTest:
+			keys = append(keys, "value")
+			members["value"] = operation.Value
+			if isNil(operation.Value) {
+				members["value"] = absser.NewUntypedNull()
+			}
+		}
+		operations[i] = NewOrderedUntypedObject(keys, members)
+	}
+	return writeUntypedJson(absser.NewUntypedArray(operations))
+}
+
+// patchDocument is the mutable copy of a document operations are applied to. Objects are held as
+// *patchObject, arrays as *patchArray and other values as the untyped nodes they were read as.
+type patchDocument struct {
+	root patchValue
+}
+
+// patchValue is a value of a patchDocument.
+type patchValue struct {
+	value interface{}
+}
+
+type patchObject struct {
+	keys    []string
+	members map[string]patchValue
+}
+
+type patchArray struct {
+	elements []patchValue
+}
+
+// newPatchDocument copies the containers of the untyped node into a patch document.
+func newPatchDocument(node absser.UntypedNodeable) *patchDocument {
+	return &patchDocument{root: newPatchValue(node)}
+}
+
+// newPatchValue copies the containers of an untyped node.
+func newPatchValue(node absser.UntypedNodeable) patchValue {
+	if isNil(node) {
+		return patchValue{value: absser.NewUntypedNull()}
+	}
+	if keys, members, ok := untypedMembers(node); ok {
+		object := &patchObject{keys: keys, members: make(map[string]patchValue, len(members))}
+		for key, member := range members {
+			object.members[key] = newPatchValue(member)
+		}
+		return patchValue{value: object}
+	}
+	if array, ok := node.(*absser.UntypedArray); ok {
+		elements := make([]patchValue, len(array.GetValue()))
+		for i, element := range array.GetValue() {
+			elements[i] = newPatchValue(element)
+		}
+		return patchValue{value: &patchArray{elements: elements}}
+	}
+	return patchValue{value: node}
+}
+
+// toUntyped returns the untyped node of the value.
+func (v patchValue) toUntyped() absser.UntypedNodeable {
+	switch value := v.value.(type) {
+	case *patchObject:
+		members := make(map[string]absser.UntypedNodeable, len(value.members))
+		for key, member := range value.members {
+			members[key] = member.toUntyped()
+		}
+		return NewOrderedUntypedObject(value.keys, members)
+	case *patchArray:
+		elements := make([]absser.UntypedNodeable, len(value.elements))
+		for i, element := range value.elements {
+			elements[i] = element.toUntyped()
+		}
+		return absser.NewUntypedArray(elements)
+	default:
+		return value.(absser.UntypedNodeable)
+	}
+}
+
+// clone copies the containers of the value.
+func (v patchValue) clone() patchValue {
+	return newPatchValue(v.toUntyped())
+}
+
+// apply applies an operation to the document.
+func (d *patchDocument) apply(operation JsonPatchOperation) error {
+	switch operation.Op {
+	case JsonPatchAdd:
+		return d.add(operation.Path, newPatchValue(operation.Value))
+	case JsonPatchRemove:
+		_, err := d.remove(operation.Path)
+		return err
+	case JsonPatchReplace:
+		return d.replace(operation.Path, newPatchValue(operation.Value))
+	case JsonPatchMove:
+		if operation.From == operation.Path {
+			_, err := d.get(operation.From)
+			return err
+		}
+		if strings.HasPrefix(operation.Path, operation.From+"/") {
+			return fmt.Errorf("a value cannot be moved into one of its children, %q", operation.From)
+		}
+		value, err := d.remove(operation.From)
+		if err != nil {
+			return err
+		}
+		return d.add(operation.Path, value)
+	case JsonPatchCopy:
+		value, err := d.get(operation.From)
+		if err != nil {
+			return err
+		}
+		return d.add(operation.Path, value.clone())
+	case JsonPatchTest:
+		value, err := d.get(operation.Path)
+		if err != nil {
+			return err
+		}
+		if !untypedNodesEqual(value.toUntyped(), operation.Value) {
+			return errors.New("the value is not the tested value")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown operation %q", operation.Op)
+	}
+}
+
+// splitPointer returns the JSON Pointer of the parent of the value and the unescaped last reference token.
+func splitPointer(pointer string) (string, string, error) {
+	if pointer == "" || pointer[0] != '/' {
+		return "", "", fmt.Errorf("invalid JSON pointer %q: it must start with '/'", pointer)
+	}
+	i := strings.LastIndexByte(pointer, '/')
+	token, err := unescapePointerToken(pointer[i+1:])
+	if err != nil {
+		return "", "", fmt.Errorf("invalid JSON pointer %q: %w", pointer, err)
+	}
+	return pointer[:i], token, nil
+}
+
+// get returns the value located at the JSON Pointer.
+func (d *patchDocument) get(pointer string) (patchValue, error) {
+	if pointer == "" {
+		return d.root, nil
+	}
+	parentPointer, token, err := splitPointer(pointer)
+	if err != nil {
+		return patchValue{}, err
+	}
+	parent, err := d.get(parentPointer)
+	if err != nil {
+		return patchValue{}, err
+	}
+	switch container := parent.value.(type) {
+	case *patchObject:
+		value, ok := container.members[token]
+		if !ok {
+			return patchValue{}, fmt.Errorf("no value is located at %q", pointer)
+		}
+		return value, nil
+	case *patchArray:
+		index, ok := pointerIndex(token)
+		if !ok || index >= len(container.elements) {
+			return patchValue{}, fmt.Errorf("no value is located at %q", pointer)
+		}
+		return container.elements[index], nil
+	default:
+		return patchValue{}, fmt.Errorf("no value is located at %q", pointer)
+	}
+}
+
+// add adds the value at the JSON Pointer, replacing the member of an object or inserting the element of an
+// array, "-" appending it.
+func (d *patchDocument) add(pointer string, value patchValue) error {
+	if pointer == "" {
+		d.root = value
+		return nil
+	}
+	parentPointer, token, err := splitPointer(pointer)
+	if err != nil {
+		return err
+	}
+	parent, err := d.get(parentPointer)
+	if err != nil {
+		return err
+	}
+	switch container := parent.value.(type) {
+	case *patchObject:
+		if _, ok := container.members[token]; !ok {
+			container.keys = append(container.keys, token)
+		}
+		container.members[token] = value
+		return nil
+	case *patchArray:
+		index := len(container.elements)
+		if token != "-" {
+			var ok bool
+			index, ok = pointerIndex(token)
+			if !ok || index > len(container.elements) {
+				return fmt.Errorf("index %q is out of the bounds of the array at %q", token, parentPointer)
+			}
+		}
+		container.elements = append(container.elements, patchValue{})
+		copy(container.elements[index+1:], container.elements[index:])
+		container.elements[index] = value
+		return nil
+	default:
+		return fmt.Errorf("the value at %q is neither an object nor an array", parentPointer)
+	}
+}
+
+// replace replaces the value located at the JSON Pointer, members keeping their position.
+func (d *patchDocument) replace(pointer string, value patchValue) error {
+	if _, err := d.get(pointer); err != nil {
+		return err
+	}
+	if pointer == "" {
+		d.root = value
+		return nil
+	}
+	parentPointer, token, _ := splitPointer(pointer)
+	parent, _ := d.get(parentPointer)
+	switch container := parent.value.(type) {
+	case *patchObject:
+		container.members[token] = value
+	case *patchArray:
+		index, _ := pointerIndex(token)
+		container.elements[index] = value
+	}
+	return nil
+}
+
+// remove removes the value located at the JSON Pointer and returns it.
+func (d *patchDocument) remove(pointer string) (patchValue, error) {
+	if pointer == "" {
+		return patchValue{}, errors.New("the document cannot be removed")
+	}
+	value, err := d.get(pointer)
+	if err != nil {
+		return patchValue{}, err
+	}
+	parentPointer, token, _ := splitPointer(pointer)
+	parent, _ := d.get(parentPointer)
+	switch container := parent.value.(type) {
+	case *patchObject:
+		delete(container.members, token)
+		for i, key := range container.keys {
+			if key == token {
+				container.keys = append(container.keys[:i:i], container.keys[i+1:]...)
+				break
+			}
+		}
+	case *patchArray:
+		index, _ := pointerIndex(token)
+		container.elements = append(container.elements[:index], container.elements[index+1:]...)
+	}
+	return value, nil
+}
+
+// CreateJsonPatch returns a JSON Patch document turning the value of the original node into the value of
+// the modified node. Objects are compared member by member and arrays element by element, the elements
+// being aligned to need as few operations as possible. Other values that differ are replaced.
+func CreateJsonPatch(original, modified *JsonParseNode) (JsonPatch, error) {
+	var originalNode, modifiedNode absser.UntypedNodeable = absser.NewUntypedNull(), absser.NewUntypedNull()
+	var err error
+	if !isNil(original) {
+		if originalNode, err = original.getUntypedValue(); err != nil {
+			return nil, err
+		}
+	}
+	if !isNil(modified) {
+		if modifiedNode, err = modified.getUntypedValue(); err != nil {
+			return nil, err
+		}
+	}
+	return appendJsonPatchDiff(JsonPatch{}, "", originalNode, modifiedNode), nil
+}
+
+// escapePointerToken encodes the ~ and / characters of a JSON Pointer reference token.
+func escapePointerToken(token string) string {
+	if !strings.ContainsAny(token, "~/") {
+		return token
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// appendJsonPatchDiff appends the operations turning the original value at the pointer into the modified one.
+func appendJsonPatchDiff(patch JsonPatch, pointer string, original, modified absser.UntypedNodeable) JsonPatch {
+	if untypedNodesEqual(original, modified) {
+		return patch
+	}
+	originalKeys, originalMembers, originalIsObject := untypedMembers(original)
+	modifiedKeys, modifiedMembers, modifiedIsObject := untypedMembers(modified)
+	if originalIsObject && modifiedIsObject {
+		for _, key := range originalKeys {
+			if _, ok := modifiedMembers[key]; !ok {
+				patch = append(patch, JsonPatchOperation{Op: JsonPatchRemove, Path: pointer + "/" + escapePointerToken(key)})
+			}
+		}
+		for _, key := range modifiedKeys {
+			path := pointer + "/" + escapePointerToken(key)
+			if originalMember, ok := originalMembers[key]; ok {
+				patch = appendJsonPatchDiff(patch, path, originalMember, modifiedMembers[key])
+			} else {
+				patch = append(patch, JsonPatchOperation{Op: JsonPatchAdd, Path: path, Value: modifiedMembers[key]})
+			}
+		}
+		return patch
+	}
+	originalArray, originalIsArray := original.(*absser.UntypedArray)
+	modifiedArray, modifiedIsArray := modified.(*absser.UntypedArray)
+	if originalIsArray && modifiedIsArray {
+		return appendJsonPatchArrayDiff(patch, pointer, originalArray, modifiedArray)
+	}
+	return append(patch, JsonPatchOperation{Op: JsonPatchReplace, Path: pointer, Value: modified})
+}
+
+// maxJsonPatchArrayDiffCells bounds the edit distance table of an array diff, whose size is the product of
+// the numbers of elements that differ between the ends the arrays share. Arrays differing more are
+// replaced whole.
+const maxJsonPatchArrayDiffCells = 1 << 20
+
+// appendJsonPatchArrayDiff appends the operations turning the original elements into the modified ones,
+// following the edit script with the fewest insertions, removals and changes. Operations are emitted from
+// the end of the array so the indices of the elements they target are not shifted by the previous ones.
+func appendJsonPatchArrayDiff(patch JsonPatch, pointer string, originalArray, modifiedArray *absser.UntypedArray) JsonPatch {
+	original, modified := originalArray.GetValue(), modifiedArray.GetValue()
+	originalIds, modifiedIds := untypedNodeIds(original, modified)
+	// the elements the arrays share at both ends need no operation
+	start := 0
+	for start < len(original) && start < len(modified) && originalIds[start] == modifiedIds[start] {
+		start++
+	}
+	rows, columns := len(original)-start, len(modified)-start
+	for rows > 0 && columns > 0 && originalIds[start+rows-1] == modifiedIds[start+columns-1] {
+		rows, columns = rows-1, columns-1
+	}
+	if (rows+1)*(columns+1) > maxJsonPatchArrayDiffCells {
+		return append(patch, JsonPatchOperation{Op: JsonPatchReplace, Path: pointer, Value: modifiedArray})
+	}
+	equal := func(i, j int) bool {
+		return originalIds[start+i-1] == modifiedIds[start+j-1]
+	}
+	// distances[i][j] is the number of edits turning the first i differing original elements into the
+	// first j differing modified ones
+	distances := make([][]int, rows+1)
+	for i := range distances {
+		distances[i] = make([]int, columns+1)
+		distances[i][0] = i
+	}
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+	for i := 1; i <= rows; i++ {
+		for j := 1; j <= columns; j++ {
+			if equal(i, j) {
+				distances[i][j] = distances[i-1][j-1]
+				continue
+			}
+			distances[i][j] = 1 + min(distances[i-1][j-1], distances[i-1][j], distances[i][j-1])
+		}
+	}
+	i, j := rows, columns
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && equal(i, j) && distances[i][j] == distances[i-1][j-1]:
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && distances[i][j] == distances[i-1][j-1]+1:
+			patch = appendJsonPatchDiff(patch, fmt.Sprintf("%s/%d", pointer, start+i-1), original[start+i-1], modified[start+j-1])
+			i, j = i-1, j-1
+		case i > 0 && distances[i][j] == distances[i-1][j]+1:
+			patch = append(patch, JsonPatchOperation{Op: JsonPatchRemove, Path: fmt.Sprintf("%s/%d", pointer, start+i-1)})
+			i--
+		default:
+			patch = append(patch, JsonPatchOperation{Op: JsonPatchAdd, Path: fmt.Sprintf("%s/%d", pointer, start+i), Value: modified[start+j-1]})
+			j--
+		}
+	}
+	return patch
+}
+
+// untypedNodeIds returns ids for the elements of both arrays, equal elements sharing an id, so elements are
+// compared deeply once rather than for each cell of the edit distance table. Elements are grouped by hash
+// before they are compared.
+func untypedNodeIds(original, modified []absser.UntypedNodeable) ([]int, []int) {
+	seed := maphash.MakeSeed()
+	buckets := make(map[uint64][]int)
+	var representatives []absser.UntypedNodeable
+	ids := func(elements []absser.UntypedNodeable) []int {
+		result := make([]int, len(elements))
+	elements:
+		for index, element := range elements {
+			hash := untypedNodeHash(seed, element)
+			for _, id := range buckets[hash] {
+				if untypedNodesEqual(representatives[id], element) {
+					result[index] = id
+					continue elements
+				}
+			}
+			result[index] = len(representatives)
+			buckets[hash] = append(buckets[hash], len(representatives))
+			representatives = append(representatives, element)
+		}
+		return result
+	}
+	return ids(original), ids(modified)
+}
+
+// untypedNodeHash returns a hash of an untyped node that is the same for the nodes untypedNodesEqual finds
+// equal: members are combined regardless of their order and numbers are hashed by their float64 value.
+func untypedNodeHash(seed maphash.Seed, node absser.UntypedNodeable) uint64 {
+	const prime = 1099511628211
+	if isUntypedNull(node) {
+		return 1
+	}
+	if _, members, ok := untypedMembers(node); ok {
+		hash := uint64(2)
+		for key, member := range members {
+			hash += maphash.String(seed, key)*prime ^ untypedNodeHash(seed, member)
+		}
+		return hash
+	}
+	if array, ok := node.(*absser.UntypedArray); ok {
+		hash := uint64(3)
+		for _, element := range array.GetValue() {
+			hash = hash*prime + untypedNodeHash(seed, element)
+		}
+		return hash
+	}
+	if text, ok := untypedNumberText(node); ok {
+		number, _ := strconv.ParseFloat(text, 64)
+		if number == 0 {
+			// negative zero included
+			return 4
+		}
+		return math.Float64bits(number) * prime
+	}
+	switch value := node.(type) {
+	case *absser.UntypedString:
+		if value.GetValue() != nil {
+			return maphash.String(seed, *value.GetValue())
+		}
+	case *absser.UntypedBoolean:
+		if value.GetValue() != nil && *value.GetValue() {
+			return 5
+		}
+	}
+	return 6
+}
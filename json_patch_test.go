@@ -0,0 +1,231 @@
+package jsonserialization
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyJsonPatch(t *testing.T) {
+	// the examples of appendix A of RFC 6902
+	tests := []struct {
+		Target   string
+		Patch    string
+		Expected string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`, `{"foo":"bar","baz":"qux"}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+		{`{"foo":null}`, `[{"op":"test","path":"/foo","value":null}]`, `{"foo":null}`},
+		{`{"foo":{"foo":1,"bar":2}}`, `[{"op":"test","path":"/foo","value":{"bar":2,"foo":1}}]`, `{"foo":{"foo":1,"bar":2}}`},
+		{`{"foo":"bar"}`, `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/foo","value":1.50}]`, `{"foo":1.50,"baz":"bar"}`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+	}
+	for _, test := range tests {
+		t.Run(test.Patch, func(t *testing.T) {
+			result, err := ApplyJsonPatch([]byte(test.Target), []byte(test.Patch))
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, string(result))
+		})
+	}
+}
+
+func TestApplyJsonPatchErrors(t *testing.T) {
+	tests := []struct {
+		Target string
+		Patch  string
+		Index  int
+	}{
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, 0},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, 0},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/2","value":"qux"}]`, 0},
+		{`{"foo":["bar"]}`, `[{"op":"remove","path":"/foo/01"}]`, 0},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, 0},
+		{`{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`, 0},
+		{`{"foo":"bar"}`, `[{"op":"copy","from":"/baz","path":"/qux"}]`, 0},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":""}]`, 0},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":1},{"op":"remove","path":"/qux"}]`, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.Patch, func(t *testing.T) {
+			_, err := ApplyJsonPatch([]byte(test.Target), []byte(test.Patch))
+			var patchErr *JsonPatchError
+			require.True(t, errors.As(err, &patchErr), "%v", err)
+			assert.Equal(t, test.Index, patchErr.Index)
+		})
+	}
+}
+
+func TestJsonPatchIsAtomic(t *testing.T) {
+	target, err := parseUntypedJson([]byte(`{"foo":["bar"],"baz":{"qux":1}}`))
+	require.NoError(t, err)
+	patch, err := ParseJsonPatch([]byte(`[
+		{"op":"add","path":"/foo/0","value":"first"},
+		{"op":"remove","path":"/baz/qux"},
+		{"op":"add","path":"/new","value":true},
+		{"op":"test","path":"/foo/1","value":"baz"}
+	]`))
+	require.NoError(t, err)
+
+	result, err := patch.Apply(target)
+	assert.Nil(t, result)
+	var patchErr *JsonPatchError
+	require.True(t, errors.As(err, &patchErr))
+	assert.Equal(t, 3, patchErr.Index)
+	assert.Equal(t, JsonPatchTest, patchErr.Op)
+	assert.Equal(t, "/foo/1", patchErr.Path)
+
+	content, err := writeUntypedJson(target)
+	require.NoError(t, err)
+	assert.Equal(t, `{"foo":["bar"],"baz":{"qux":1}}`, string(content))
+}
+
+func TestParseJsonPatchErrors(t *testing.T) {
+	tests := []string{
+		`{"op":"remove","path":"/a"}`,
+		`[{"path":"/a"}]`,
+		`[{"op":"remove"}]`,
+		`[{"op":"add","path":"/a"}]`,
+		`[{"op":"move","path":"/a"}]`,
+		`[{"op":"delete","path":"/a"}]`,
+		`[{"op":1,"path":"/a"}]`,
+		`["remove"]`,
+	}
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			_, err := ParseJsonPatch([]byte(test))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJsonPatchGetSerializedContent(t *testing.T) {
+	content := `[{"op":"add","path":"/a","value":{"b":[1,null]}},{"op":"remove","path":"/c"},{"op":"move","path":"/d","from":"/e"},{"op":"test","path":"/f","value":null}]`
+	patch, err := ParseJsonPatch([]byte(content))
+	require.NoError(t, err)
+	require.Len(t, patch, 4)
+	assert.Equal(t, "/e", patch[2].From)
+
+	serialized, err := patch.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, content, string(serialized))
+}
+
+func TestCreateJsonPatch(t *testing.T) {
+	tests := []struct {
+		Original string
+		Modified string
+		Expected string
+	}{
+		{`{"a":1,"b":2}`, `{"a":1,"b":2}`, `[]`},
+		{`{"a":1,"b":2}`, `{"a":1,"c":3}`, `[{"op":"remove","path":"/b"},{"op":"add","path":"/c","value":3}]`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d"}}`, `[{"op":"replace","path":"/a/b","value":"d"}]`},
+		{`{"a/b":1,"c~d":2}`, `{"a/b":2,"c~d":2}`, `[{"op":"replace","path":"/a~1b","value":2}]`},
+		{`[1,2,3]`, `[1,3]`, `[{"op":"remove","path":"/1"}]`},
+		{`[1,3]`, `[0,1,2,3,4]`, `[{"op":"add","path":"/2","value":4},{"op":"add","path":"/1","value":2},{"op":"add","path":"/0","value":0}]`},
+		{`[{"a":1},{"b":2}]`, `[{"a":1},{"b":3}]`, `[{"op":"replace","path":"/1/b","value":3}]`},
+		{`{"a":[1]}`, `{"a":"x"}`, `[{"op":"replace","path":"/a","value":"x"}]`},
+		{`1`, `2.0`, `[{"op":"replace","path":"","value":2.0}]`},
+	}
+	for _, test := range tests {
+		t.Run(test.Original+" "+test.Modified, func(t *testing.T) {
+			original, err := NewJsonParseNode([]byte(test.Original), WithLosslessRoundTrip())
+			require.NoError(t, err)
+			modified, err := NewJsonParseNode([]byte(test.Modified), WithLosslessRoundTrip())
+			require.NoError(t, err)
+
+			patch, err := CreateJsonPatch(original, modified)
+			require.NoError(t, err)
+			content, err := patch.GetSerializedContent()
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, string(content))
+
+			result, err := ApplyJsonPatch([]byte(test.Original), content)
+			require.NoError(t, err)
+			resultNode, err := parseUntypedJson(result)
+			require.NoError(t, err)
+			modifiedNode, err := parseUntypedJson([]byte(test.Modified))
+			require.NoError(t, err)
+			assert.True(t, untypedNodesEqual(modifiedNode, resultNode), string(result))
+		})
+	}
+}
+
+func TestCreateJsonPatchOfArrays(t *testing.T) {
+	tests := [][2]string{
+		{`["a","b","c","d"]`, `["b","x","d","e"]`},
+		{`[]`, `[1,2,3]`},
+		{`[1,2,3]`, `[]`},
+		{`[[1,2],[3]]`, `[[1],[3,4],[5]]`},
+		{`{"list":[{"id":1},{"id":2},{"id":3}]}`, `{"list":[{"id":3},{"id":1}]}`},
+	}
+	for _, test := range tests {
+		t.Run(test[0]+" "+test[1], func(t *testing.T) {
+			original, err := NewJsonParseNode([]byte(test[0]), WithLosslessRoundTrip())
+			require.NoError(t, err)
+			modified, err := NewJsonParseNode([]byte(test[1]), WithLosslessRoundTrip())
+			require.NoError(t, err)
+			patch, err := CreateJsonPatch(original, modified)
+			require.NoError(t, err)
+
+			result, err := patch.ApplyToNode(original)
+			require.NoError(t, err)
+			modifiedNode, err := parseUntypedJson([]byte(test[1]))
+			require.NoError(t, err)
+			assert.True(t, untypedNodesEqual(modifiedNode, result))
+		})
+	}
+}
+
+func TestCreateJsonPatchOfLargeArrays(t *testing.T) {
+	elements := func(count int, element func(i int) string) []byte {
+		parts := make([]string, count)
+		for i := range parts {
+			parts[i] = element(i)
+		}
+		return []byte("[" + strings.Join(parts, ",") + "]")
+	}
+	diff := func(original, modified []byte) string {
+		originalNode, err := NewJsonParseNode(original)
+		require.NoError(t, err)
+		modifiedNode, err := NewJsonParseNode(modified)
+		require.NoError(t, err)
+		patch, err := CreateJsonPatch(originalNode, modifiedNode)
+		require.NoError(t, err)
+		content, err := patch.GetSerializedContent()
+		require.NoError(t, err)
+		return string(content)
+	}
+	const count = 5000
+	original := elements(count, func(i int) string { return fmt.Sprintf(`{"id":%d}`, i) })
+
+	// the elements shared at both ends are skipped, so a change in a large array stays small
+	changed := elements(count, func(i int) string {
+		if i == 2500 {
+			return `{"id":"changed"}`
+		}
+		return fmt.Sprintf(`{"id":%d}`, i)
+	})
+	assert.Equal(t, `[{"op":"replace","path":"/2500/id","value":"changed"}]`, diff(original, changed))
+
+	// arrays differing throughout are replaced whole
+	reversed := elements(count, func(i int) string { return fmt.Sprintf(`{"id":%d}`, count-1-i) })
+	patch := diff(original, reversed)
+	assert.True(t, strings.HasPrefix(patch, `[{"op":"replace","path":"","value":[{"id":4999},`), patch[:80])
+	result, err := ApplyJsonPatch(original, []byte(patch))
+	require.NoError(t, err)
+	assert.JSONEq(t, string(reversed), string(result))
+}
@@ -0,0 +1,888 @@
+package jsonserialization
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// GetNodesAtPath returns the nodes selected by a JSONPath query (RFC 9535) evaluated with the node as
+// the root. The supported subset covers the root identifier, child and descendant segments (.name,
+// .*, ..name, ..* and bracketed selectors), and name, wildcard, index, slice and filter selectors.
+// Filters support existence tests, comparisons between literals and singular queries, parentheses and
+// the !, && and || operators. Function extensions are not supported.
+// Nodes are returned in the order the query selects them, JSON nulls as nodes whose getters return nil,
+// and keep the before and after hooks of the node, as GetChildNode does.
+func (n *JsonParseNode) GetNodesAtPath(path string) ([]*JsonParseNode, error) {
+	query, err := parseJsonPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if isNil(n) {
+		return nil, nil
+	}
+	return query.evaluate(n, n)
+}
+
+// jsonPathQuery is a parsed JSONPath query.
+type jsonPathQuery struct {
+	// relative is set for the queries of filters starting with @, which apply to the current node.
+	relative bool
+	segments []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	// descendant is set when the selectors apply to the node and all its descendants.
+	descendant bool
+	selectors  []jsonPathSelector
+}
+
+type jsonPathSelectorKind int
+
+const (
+	nameSelector jsonPathSelectorKind = iota
+	wildcardSelector
+	indexSelector
+	sliceSelector
+	filterSelector
+)
+
+type jsonPathSelector struct {
+	kind  jsonPathSelectorKind
+	name  string
+	index int
+	// start and end are only used when hasStart and hasEnd are set, step is never 0 unless given as 0.
+	start, end       int
+	hasStart, hasEnd bool
+	step             int
+	filter           filterExpression
+}
+
+// evaluate returns the nodes selected by the query, root being the node of $ and current the node of @.
+func (q *jsonPathQuery) evaluate(root, current *JsonParseNode) ([]*JsonParseNode, error) {
+	nodes := []*JsonParseNode{root}
+	if q.relative {
+		nodes = []*JsonParseNode{current}
+	}
+	for i := range q.segments {
+		var selected []*JsonParseNode
+		for _, node := range nodes {
+			var err error
+			selected, err = q.segments[i].apply(root, node, selected)
+			if err != nil {
+				return nil, err
+			}
+		}
+		nodes = selected
+	}
+	return nodes, nil
+}
+
+// isSingular tells whether the query selects at most one node whatever the value it applies to.
+func (q *jsonPathQuery) isSingular() bool {
+	for _, segment := range q.segments {
+		if segment.descendant || len(segment.selectors) != 1 {
+			return false
+		}
+		if kind := segment.selectors[0].kind; kind != nameSelector && kind != indexSelector {
+			return false
+		}
+	}
+	return true
+}
+
+// apply appends the nodes the segment selects from node to result, descendants being visited in document order.
+func (s *jsonPathSegment) apply(root, node *JsonParseNode, result []*JsonParseNode) ([]*JsonParseNode, error) {
+	for i := range s.selectors {
+		var err error
+		result, err = s.selectors[i].apply(root, node, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !s.descendant {
+		return result, nil
+	}
+	children, err := node.children()
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		result, err = s.apply(root, child, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// apply appends the children of node the selector selects to result.
+func (s *jsonPathSelector) apply(root, node *JsonParseNode, result []*JsonParseNode) ([]*JsonParseNode, error) {
+	switch s.kind {
+	case nameSelector:
+		if properties, ok := node.value.(map[string]interface{}); ok {
+			if rawValue, ok := properties[s.name]; ok {
+				child, err := node.childOrNull(s.name, -1, rawValue)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, child)
+			}
+		}
+	case indexSelector:
+		if elements, ok := node.value.([]interface{}); ok {
+			index := s.index
+			if index < 0 {
+				index += len(elements)
+			}
+			if index >= 0 && index < len(elements) {
+				child, err := node.childOrNull("", index, elements[index])
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, child)
+			}
+		}
+	case sliceSelector:
+		if elements, ok := node.value.([]interface{}); ok {
+			for _, index := range s.sliceIndices(len(elements)) {
+				child, err := node.childOrNull("", index, elements[index])
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, child)
+			}
+		}
+	case wildcardSelector, filterSelector:
+		children, err := node.children()
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if s.kind == filterSelector {
+				selected, err := s.filter.test(root, child)
+				if err != nil {
+					return nil, err
+				}
+				if !selected {
+					continue
+				}
+			}
+			result = append(result, child)
+		}
+	}
+	return result, nil
+}
+
+// sliceIndices returns the indices a slice selector selects in an array of the given length.
+func (s *jsonPathSelector) sliceIndices(length int) []int {
+	if s.step == 0 {
+		return nil
+	}
+	normalize := func(index int) int {
+		if index < 0 {
+			return length + index
+		}
+		return index
+	}
+	var indices []int
+	if s.step > 0 {
+		lower, upper := 0, length
+		if s.hasStart {
+			lower = min(max(normalize(s.start), 0), length)
+		}
+		if s.hasEnd {
+			upper = min(max(normalize(s.end), 0), length)
+		}
+		for i := lower; i < upper; i += s.step {
+			indices = append(indices, i)
+		}
+		return indices
+	}
+	upper, lower := length-1, -1
+	if s.hasStart {
+		upper = min(max(normalize(s.start), -1), length-1)
+	}
+	if s.hasEnd {
+		lower = min(max(normalize(s.end), -1), length-1)
+	}
+	for i := upper; lower < i; i += s.step {
+		indices = append(indices, i)
+	}
+	return indices
+}
+
+// filterExpression is the logical expression of a filter selector.
+type filterExpression interface {
+	test(root, current *JsonParseNode) (bool, error)
+}
+
+type orExpression []filterExpression
+
+func (e orExpression) test(root, current *JsonParseNode) (bool, error) {
+	for _, operand := range e {
+		if result, err := operand.test(root, current); err != nil || result {
+			return result, err
+		}
+	}
+	return false, nil
+}
+
+type andExpression []filterExpression
+
+func (e andExpression) test(root, current *JsonParseNode) (bool, error) {
+	for _, operand := range e {
+		if result, err := operand.test(root, current); err != nil || !result {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+type notExpression struct {
+	operand filterExpression
+}
+
+func (e *notExpression) test(root, current *JsonParseNode) (bool, error) {
+	result, err := e.operand.test(root, current)
+	return !result, err
+}
+
+// existenceTest is true when its query selects at least one node.
+type existenceTest struct {
+	query *jsonPathQuery
+}
+
+func (e *existenceTest) test(root, current *JsonParseNode) (bool, error) {
+	nodes, err := e.query.evaluate(root, current)
+	return len(nodes) != 0, err
+}
+
+// comparison compares two literals or values of singular queries.
+type comparison struct {
+	left, right *comparisonOperand
+	operator    string
+}
+
+// comparisonOperand is a literal or a singular query. Literals and values are normalized as nil for
+// null, bool, string, *big.Rat for numbers and *JsonParseNode for objects and arrays.
+type comparisonOperand struct {
+	query   *jsonPathQuery
+	literal interface{}
+}
+
+// value returns the value of the operand, ok being false when its query selects nothing.
+func (o *comparisonOperand) value(root, current *JsonParseNode) (value interface{}, ok bool, err error) {
+	if o.query == nil {
+		return o.literal, true, nil
+	}
+	nodes, err := o.query.evaluate(root, current)
+	if err != nil || len(nodes) != 1 {
+		return nil, false, err
+	}
+	return filterValue(nodes[0]), true, nil
+}
+
+func (c *comparison) test(root, current *JsonParseNode) (bool, error) {
+	left, leftOk, err := c.left.value(root, current)
+	if err != nil {
+		return false, err
+	}
+	right, rightOk, err := c.right.value(root, current)
+	if err != nil {
+		return false, err
+	}
+	switch c.operator {
+	case "==", "!=":
+		equal, err := filterValuesEqual(left, leftOk, right, rightOk)
+		return equal == (c.operator == "=="), err
+	case "<":
+		return filterValueLess(left, leftOk, right, rightOk), nil
+	case ">":
+		return filterValueLess(right, rightOk, left, leftOk), nil
+	case "<=", ">=":
+		if c.operator == "<=" && filterValueLess(left, leftOk, right, rightOk) ||
+			c.operator == ">=" && filterValueLess(right, rightOk, left, leftOk) {
+			return true, nil
+		}
+		return filterValuesEqual(left, leftOk, right, rightOk)
+	default:
+		return false, fmt.Errorf("unknown comparison operator %q", c.operator)
+	}
+}
+
+// filterValue returns the normalized value of a node, see comparisonOperand.
+func filterValue(node *JsonParseNode) interface{} {
+	switch value := node.value.(type) {
+	case nil:
+		return nil
+	case *string:
+		return *value
+	case *bool:
+		return *value
+	case *json.Number:
+		if number, ok := new(big.Rat).SetString(value.String()); ok {
+			return number
+		}
+		return nil
+	case *float64:
+		return floatToRat(*value)
+	case *float32:
+		return floatToRat(float64(*value))
+	case *int64:
+		return new(big.Rat).SetInt64(*value)
+	case *int32:
+		return new(big.Rat).SetInt64(int64(*value))
+	case *int8:
+		return new(big.Rat).SetInt64(int64(*value))
+	case *byte:
+		return new(big.Rat).SetInt64(int64(*value))
+	default:
+		return node
+	}
+}
+
+func floatToRat(value float64) interface{} {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return new(big.Rat).SetFloat64(value)
+}
+
+// filterValueLess tells whether left is less than right, only numbers and strings being ordered.
+func filterValueLess(left interface{}, leftOk bool, right interface{}, rightOk bool) bool {
+	if !leftOk || !rightOk {
+		return false
+	}
+	switch l := left.(type) {
+	case *big.Rat:
+		r, ok := right.(*big.Rat)
+		return ok && l.Cmp(r) < 0
+	case string:
+		r, ok := right.(string)
+		return ok && l < r
+	default:
+		return false
+	}
+}
+
+// filterValuesEqual tells whether two values are equal, arrays and objects being compared deeply.
+func filterValuesEqual(left interface{}, leftOk bool, right interface{}, rightOk bool) (bool, error) {
+	if !leftOk || !rightOk {
+		return leftOk == rightOk, nil
+	}
+	switch l := left.(type) {
+	case nil:
+		return right == nil, nil
+	case bool, string:
+		return l == right, nil
+	case *big.Rat:
+		r, ok := right.(*big.Rat)
+		return ok && l.Cmp(r) == 0, nil
+	case *JsonParseNode:
+		r, ok := right.(*JsonParseNode)
+		if !ok {
+			return false, nil
+		}
+		return nodesEqual(l, r)
+	default:
+		return false, nil
+	}
+}
+
+// nodesEqual compares two objects or arrays deeply.
+func nodesEqual(left, right *JsonParseNode) (bool, error) {
+	switch l := left.value.(type) {
+	case map[string]interface{}:
+		r, ok := right.value.(map[string]interface{})
+		if !ok || len(l) != len(r) {
+			return false, nil
+		}
+		for key, leftRaw := range l {
+			rightRaw, ok := r[key]
+			if !ok {
+				return false, nil
+			}
+			leftChild, err := left.childOrNull(key, -1, leftRaw)
+			if err != nil {
+				return false, err
+			}
+			rightChild, err := right.childOrNull(key, -1, rightRaw)
+			if err != nil {
+				return false, err
+			}
+			if equal, err := filterValuesEqual(filterValue(leftChild), true, filterValue(rightChild), true); err != nil || !equal {
+				return false, err
+			}
+		}
+		return true, nil
+	case []interface{}:
+		r, ok := right.value.([]interface{})
+		if !ok || len(l) != len(r) {
+			return false, nil
+		}
+		for index := range l {
+			leftChild, err := left.childOrNull("", index, l[index])
+			if err != nil {
+				return false, err
+			}
+			rightChild, err := right.childOrNull("", index, r[index])
+			if err != nil {
+				return false, err
+			}
+			if equal, err := filterValuesEqual(filterValue(leftChild), true, filterValue(rightChild), true); err != nil || !equal {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// jsonPathParser parses JSONPath queries.
+type jsonPathParser struct {
+	path string
+	pos  int
+}
+
+// parseJsonPath parses a JSONPath query, which must start with the root identifier $.
+func parseJsonPath(path string) (*jsonPathQuery, error) {
+	p := &jsonPathParser{path: path}
+	if !p.consume("$") {
+		return nil, p.errorf("the query must start with '$'")
+	}
+	query, err := p.parseSegments(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(path) {
+		return nil, p.errorf("unexpected character %q", path[p.pos])
+	}
+	return query, nil
+}
+
+func (p *jsonPathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid JSONPath %q at offset %d: %s", p.path, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos < len(p.path) {
+		return p.path[p.pos]
+	}
+	return 0
+}
+
+func (p *jsonPathParser) consume(token string) bool {
+	if strings.HasPrefix(p.path[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipWhitespace() {
+	for p.pos < len(p.path) {
+		switch p.path[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// parseSegments parses the segments following a root or current node identifier.
+func (p *jsonPathParser) parseSegments(relative bool) (*jsonPathQuery, error) {
+	query := &jsonPathQuery{relative: relative}
+	for {
+		start := p.pos
+		p.skipWhitespace()
+		var segment jsonPathSegment
+		switch {
+		case p.consume(".."):
+			segment.descendant = true
+			if p.peek() == '[' {
+				selectors, err := p.parseBracketedSelectors()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = selectors
+			} else {
+				selector, err := p.parseShorthand()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = []jsonPathSelector{selector}
+			}
+		case p.consume("."):
+			selector, err := p.parseShorthand()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = []jsonPathSelector{selector}
+		case p.peek() == '[':
+			selectors, err := p.parseBracketedSelectors()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = selectors
+		default:
+			p.pos = start
+			return query, nil
+		}
+		query.segments = append(query.segments, segment)
+	}
+}
+
+// parseShorthand parses the wildcard or member name following a dot.
+func (p *jsonPathParser) parseShorthand() (jsonPathSelector, error) {
+	if p.consume("*") {
+		return jsonPathSelector{kind: wildcardSelector}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.path) {
+		c := p.path[p.pos]
+		if c == '_' || c >= 0x80 || (c|0x20 >= 'a' && c|0x20 <= 'z') || (p.pos > start && c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	if p.pos == start {
+		return jsonPathSelector{}, p.errorf("a member name or '*' is expected")
+	}
+	return jsonPathSelector{kind: nameSelector, name: p.path[start:p.pos]}, nil
+}
+
+// parseBracketedSelectors parses a comma separated list of selectors between brackets.
+func (p *jsonPathParser) parseBracketedSelectors() ([]jsonPathSelector, error) {
+	p.pos++
+	var selectors []jsonPathSelector
+	for {
+		p.skipWhitespace()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+		p.skipWhitespace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("',' or ']' is expected")
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		return jsonPathSelector{kind: nameSelector, name: name}, err
+	case c == '*':
+		p.pos++
+		return jsonPathSelector{kind: wildcardSelector}, nil
+	case c == '?':
+		p.pos++
+		p.skipWhitespace()
+		filter, err := p.parseOr()
+		return jsonPathSelector{kind: filterSelector, filter: filter}, err
+	}
+	selector := jsonPathSelector{kind: indexSelector, step: 1}
+	var err error
+	selector.start, selector.hasStart, err = p.parseOptionalInt()
+	if err != nil {
+		return selector, err
+	}
+	p.skipWhitespace()
+	if !p.consume(":") {
+		if !selector.hasStart {
+			return selector, p.errorf("a selector is expected")
+		}
+		selector.index = selector.start
+		return selector, nil
+	}
+	selector.kind = sliceSelector
+	p.skipWhitespace()
+	if selector.end, selector.hasEnd, err = p.parseOptionalInt(); err != nil {
+		return selector, err
+	}
+	p.skipWhitespace()
+	if p.consume(":") {
+		p.skipWhitespace()
+		step, hasStep, err := p.parseOptionalInt()
+		if err != nil {
+			return selector, err
+		}
+		if hasStep {
+			selector.step = step
+		}
+	}
+	return selector, nil
+}
+
+// maxJsonPathInt is the largest magnitude of the integers of a query, the range of I-JSON integers.
+const maxJsonPathInt = 1<<53 - 1
+
+// parseOptionalInt parses an integer if one is present, without leading zeros nor "-0".
+func (p *jsonPathParser) parseOptionalInt() (int, bool, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	for p.pos < len(p.path) && p.path[p.pos] >= '0' && p.path[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == digits {
+		if digits != start {
+			return 0, false, p.errorf("a digit is expected")
+		}
+		return 0, false, nil
+	}
+	text := p.path[start:p.pos]
+	if p.path[digits] == '0' && (p.pos-digits > 1 || digits != start) {
+		return 0, false, p.errorf("invalid integer %q", text)
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value > maxJsonPathInt || value < -maxJsonPathInt {
+		return 0, false, p.errorf("integer %q is out of range", text)
+	}
+	return value, true, nil
+}
+
+// parseString parses a string literal between single or double quotes.
+func (p *jsonPathParser) parseString() (string, error) {
+	quote := p.path[p.pos]
+	p.pos++
+	start := p.pos
+	escaped := false
+	for p.pos < len(p.path) {
+		c := p.path[p.pos]
+		switch {
+		case c == quote:
+			text := p.path[start:p.pos]
+			p.pos++
+			if !escaped {
+				return text, nil
+			}
+			return unescapeString([]byte(text)), nil
+		case c < 0x20:
+			return "", p.errorf("control characters must be escaped")
+		case c == '\\':
+			escaped = true
+			p.pos++
+			switch p.peek() {
+			case 'b', 'f', 'n', 'r', 't', '/', '\\':
+				p.pos++
+			case quote:
+				p.pos++
+			case 'u':
+				p.pos++
+				for i := 0; i < 4; i++ {
+					if hexValue(p.peek()) < 0 {
+						return "", p.errorf("invalid unicode escape")
+					}
+					p.pos++
+				}
+			default:
+				return "", p.errorf("invalid escape")
+			}
+		default:
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// parseOr parses a logical expression, || having the lowest precedence.
+func (p *jsonPathParser) parseOr() (filterExpression, error) {
+	var operands orExpression
+	for {
+		operand, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		start := p.pos
+		p.skipWhitespace()
+		if !p.consume("||") {
+			p.pos = start
+			break
+		}
+		p.skipWhitespace()
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return operands, nil
+}
+
+func (p *jsonPathParser) parseAnd() (filterExpression, error) {
+	var operands andExpression
+	for {
+		operand, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		start := p.pos
+		p.skipWhitespace()
+		if !p.consume("&&") {
+			p.pos = start
+			break
+		}
+		p.skipWhitespace()
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return operands, nil
+}
+
+// parseBasic parses a parenthesized expression, an existence test or a comparison, possibly negated.
+func (p *jsonPathParser) parseBasic() (filterExpression, error) {
+	if p.consume("!") {
+		p.skipWhitespace()
+		operand, err := p.parseNegatable()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpression{operand: operand}, nil
+	}
+	if p.peek() == '(' {
+		return p.parseNegatable()
+	}
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	start := p.pos
+	p.skipWhitespace()
+	operator := ""
+	for _, candidate := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(candidate) {
+			operator = candidate
+			break
+		}
+	}
+	if operator == "" {
+		p.pos = start
+		if left.query == nil {
+			return nil, p.errorf("a literal must be compared")
+		}
+		return &existenceTest{query: left.query}, nil
+	}
+	p.skipWhitespace()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for _, operand := range []*comparisonOperand{left, right} {
+		if operand.query != nil && !operand.query.isSingular() {
+			return nil, p.errorf("only singular queries can be compared")
+		}
+	}
+	return &comparison{left: left, right: right, operator: operator}, nil
+}
+
+// parseNegatable parses what can follow !, a parenthesized expression or an existence test.
+func (p *jsonPathParser) parseNegatable() (filterExpression, error) {
+	if !p.consume("(") {
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if operand.query == nil {
+			return nil, p.errorf("a query is expected")
+		}
+		return &existenceTest{query: operand.query}, nil
+	}
+	p.skipWhitespace()
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipWhitespace()
+	if !p.consume(")") {
+		return nil, p.errorf("')' is expected")
+	}
+	return expression, nil
+}
+
+// parseOperand parses a query or a literal.
+func (p *jsonPathParser) parseOperand() (*comparisonOperand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		query, err := p.parseSegments(c == '@')
+		if err != nil {
+			return nil, err
+		}
+		return &comparisonOperand{query: query}, nil
+	case c == '\'' || c == '"':
+		value, err := p.parseString()
+		return &comparisonOperand{literal: value}, err
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case p.consume("true"):
+		return &comparisonOperand{literal: true}, nil
+	case p.consume("false"):
+		return &comparisonOperand{literal: false}, nil
+	case p.consume("null"):
+		return &comparisonOperand{literal: nil}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.path) && p.path[p.pos] >= 'a' && p.path[p.pos] <= 'z' {
+		p.pos++
+	}
+	if p.pos > start && p.peek() == '(' {
+		p.pos = start
+		return nil, p.errorf("function extensions are not supported")
+	}
+	p.pos = start
+	return nil, p.errorf("a query or a literal is expected")
+}
+
+// parseNumber parses a number literal, following the JSON grammar with "-0" allowed.
+func (p *jsonPathParser) parseNumber() (*comparisonOperand, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	for p.pos < len(p.path) && p.path[p.pos] >= '0' && p.path[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == digits || (p.path[digits] == '0' && p.pos-digits > 1) {
+		return nil, p.errorf("invalid number")
+	}
+	if p.consume(".") {
+		fraction := p.pos
+		for p.pos < len(p.path) && p.path[p.pos] >= '0' && p.path[p.pos] <= '9' {
+			p.pos++
+		}
+		if p.pos == fraction {
+			return nil, p.errorf("invalid number")
+		}
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		exponent := p.pos
+		for p.pos < len(p.path) && p.path[p.pos] >= '0' && p.path[p.pos] <= '9' {
+			p.pos++
+		}
+		if p.pos == exponent {
+			return nil, p.errorf("invalid number")
+		}
+	}
+	number, ok := new(big.Rat).SetString(p.path[start:p.pos])
+	if !ok {
+		return nil, p.errorf("invalid number")
+	}
+	return &comparisonOperand{literal: number}, nil
+}
@@ -0,0 +1,117 @@
+package jsonserialization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jsonPathStore = `{"store":{
+  "book":[
+    {"category":"reference","author":"Nigel Rees","title":"Sayings of the Century","price":8.95},
+    {"category":"fiction","author":"Evelyn Waugh","title":"Sword of Honour","price":12.99},
+    {"category":"fiction","author":"Herman Melville","title":"Moby Dick","isbn":"0-553-21311-3","price":8.99},
+    {"category":"fiction","author":"J. R. R. Tolkien","title":"The Lord of the Rings","isbn":"0-395-19395-8","price":22.99}
+  ],
+  "bicycle":{"color":"red","price":399}
+},"limit":10}`
+
+func TestGetNodesAtPath(t *testing.T) {
+	parseNode, err := NewJsonParseNode([]byte(jsonPathStore))
+	require.NoError(t, err)
+
+	tests := []struct {
+		Path     string
+		Expected []string
+	}{
+		{"$.store.book[*].author", []string{"/store/book/0/author", "/store/book/1/author", "/store/book/2/author", "/store/book/3/author"}},
+		{"$..author", []string{"/store/book/0/author", "/store/book/1/author", "/store/book/2/author", "/store/book/3/author"}},
+		{"$.store.*", []string{"/store/book", "/store/bicycle"}},
+		{"$.store..price", []string{"/store/book/0/price", "/store/book/1/price", "/store/book/2/price", "/store/book/3/price", "/store/bicycle/price"}},
+		{"$..book[2]", []string{"/store/book/2"}},
+		{"$..book[-1]", []string{"/store/book/3"}},
+		{"$..book[0,1]", []string{"/store/book/0", "/store/book/1"}},
+		{"$..book[:2]", []string{"/store/book/0", "/store/book/1"}},
+		{"$..book[::-2]", []string{"/store/book/3", "/store/book/1"}},
+		{"$..book[1:3:0]", nil},
+		{"$..book[?@.isbn]", []string{"/store/book/2", "/store/book/3"}},
+		{"$..book[?!@.isbn].title", []string{"/store/book/0/title", "/store/book/1/title"}},
+		{"$..book[?@.price<10].title", []string{"/store/book/0/title", "/store/book/2/title"}},
+		{"$..book[?@.price < $.limit && @.category == 'fiction']", []string{"/store/book/2"}},
+		{"$..book[?(@.price > 20 || @.author == \"Nigel Rees\")]", []string{"/store/book/0", "/store/book/3"}},
+		{"$..book[?@.price >= 12.99 && !(@.price > 12.99)]", []string{"/store/book/1"}},
+		{"$['store'][\"bicycle\"]['color']", []string{"/store/bicycle/color"}},
+		{"$..[?@ == 399]", []string{"/store/bicycle/price"}},
+		{"$.store[?@.color == 'red']", []string{"/store/bicycle"}},
+		{"$.missing", nil},
+		{"$", []string{""}},
+	}
+	for _, test := range tests {
+		t.Run(test.Path, func(t *testing.T) {
+			nodes, err := parseNode.GetNodesAtPath(test.Path)
+			require.NoError(t, err)
+			var pointers []string
+			for _, node := range nodes {
+				pointers = append(pointers, node.pointer())
+			}
+			assert.Equal(t, test.Expected, pointers)
+		})
+	}
+}
+
+func TestGetNodesAtPathComparesValues(t *testing.T) {
+	parseNode, err := NewJsonParseNode([]byte(`[{"a":[1,{"b":null}]},{"a":[1.0,{"b":null}]},{"a":[1,{"b":false}]},{"a":"x"},{}]`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		Path     string
+		Expected []string
+	}{
+		{"$[?@.a == $[0].a]", []string{"/0", "/1"}},
+		{"$[?@.a != $[0].a]", []string{"/2", "/3", "/4"}},
+		{"$[?@.a[1].b == null]", []string{"/0", "/1"}},
+		{"$[?@.c == @.d]", []string{"/0", "/1", "/2", "/3", "/4"}},
+		{"$[?@.a < 'y']", []string{"/3"}},
+		{"$[?@.a <= 1]", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.Path, func(t *testing.T) {
+			nodes, err := parseNode.GetNodesAtPath(test.Path)
+			require.NoError(t, err)
+			var pointers []string
+			for _, node := range nodes {
+				pointers = append(pointers, node.pointer())
+			}
+			assert.Equal(t, test.Expected, pointers)
+		})
+	}
+}
+
+func TestGetNodesAtPathReadsValues(t *testing.T) {
+	parseNode, err := NewJsonParseNode([]byte(jsonPathStore), WithLazyLoading())
+	require.NoError(t, err)
+
+	nodes, err := parseNode.GetNodesAtPath("$..book[?@.isbn].title")
+	require.NoError(t, err)
+	var titles []string
+	for _, node := range nodes {
+		title, err := node.GetStringValue()
+		require.NoError(t, err)
+		titles = append(titles, *title)
+	}
+	assert.Equal(t, []string{"Moby Dick", "The Lord of the Rings"}, titles)
+}
+
+func TestGetNodesAtPathRejectsInvalidQueries(t *testing.T) {
+	parseNode, err := NewJsonParseNode([]byte(`{}`))
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		"", "store", "$.", "$[", "$[1", "$[01]", "$[-0]", "$['a]", "$['\\q']", "$[?@.a == 1 &&]",
+		"$[?1]", "$[?@..a == 1]", "$[?@.* == 1]", "$[?length(@) == 1]", "$.a ", "$[9007199254740992]",
+	} {
+		_, err := parseNode.GetNodesAtPath(path)
+		assert.Error(t, err, path)
+	}
+}
@@ -0,0 +1,132 @@
+package jsonserialization
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetNodeAtPointer returns the node located at the RFC 6901 JSON Pointer, relative to the node. It returns
+// nil when no value is located at the pointer, and a node whose getters return nil for JSON nulls.
+// The returned node keeps the before and after hooks of the node, as GetChildNode does.
+func (n *JsonParseNode) GetNodeAtPointer(pointer string) (*JsonParseNode, error) {
+	if isNil(n) {
+		return nil, nil
+	}
+	if pointer == "" {
+		return n, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: it must be empty or start with '/'", pointer)
+	}
+	node := n
+	for _, token := range strings.Split(pointer[1:], "/") {
+		key, err := unescapePointerToken(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON pointer %q: %w", pointer, err)
+		}
+		switch value := node.value.(type) {
+		case map[string]interface{}:
+			rawValue, ok := value[key]
+			if !ok {
+				return nil, nil
+			}
+			node, err = node.childOrNull(key, -1, rawValue)
+		case []interface{}:
+			index, ok := pointerIndex(key)
+			if !ok || index >= len(value) {
+				return nil, nil
+			}
+			node, err = node.childOrNull("", index, value[index])
+		default:
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// unescapePointerToken decodes the ~0 and ~1 escapes of a JSON Pointer reference token.
+func unescapePointerToken(token string) (string, error) {
+	if !strings.Contains(token, "~") {
+		return token, nil
+	}
+	var builder strings.Builder
+	for i := 0; i < len(token); i++ {
+		if token[i] != '~' {
+			builder.WriteByte(token[i])
+			continue
+		}
+		if i+1 == len(token) || (token[i+1] != '0' && token[i+1] != '1') {
+			return "", fmt.Errorf("invalid escape in reference token %q", token)
+		}
+		if token[i+1] == '0' {
+			builder.WriteByte('~')
+		} else {
+			builder.WriteByte('/')
+		}
+		i++
+	}
+	return builder.String(), nil
+}
+
+// pointerIndex parses an array index of a JSON Pointer, which has no sign nor leading zeros.
+func pointerIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(token)
+	return index, err == nil
+}
+
+// childOrNull returns the node of a child value with the given key or index, index being -1 for members.
+// Unlike wrapChild, JSON nulls are returned as nodes holding no value.
+func (n *JsonParseNode) childOrNull(key string, index int, rawValue interface{}) (*JsonParseNode, error) {
+	if rawValue != nil {
+		if index < 0 {
+			return n.memberNode(key, rawValue)
+		}
+		return n.elementNode(index, rawValue)
+	}
+	offset := int64(-1)
+	if index >= 0 {
+		offset = n.elementOffset(index)
+	}
+	return n.adopt(&JsonParseNode{offset: offset, source: n.source}, key, index)
+}
+
+// children returns the nodes of the members of the object in payload order or of the elements of the array.
+func (n *JsonParseNode) children() ([]*JsonParseNode, error) {
+	switch value := n.value.(type) {
+	case map[string]interface{}:
+		keys := orderedKeys(n.memberKeys(), value)
+		result := make([]*JsonParseNode, 0, len(keys))
+		for _, key := range keys {
+			child, err := n.childOrNull(key, -1, value[key])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, child)
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]*JsonParseNode, 0, len(value))
+		for index, rawValue := range value {
+			child, err := n.childOrNull("", index, rawValue)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, child)
+		}
+		return result, nil
+	default:
+		return nil, nil
+	}
+}
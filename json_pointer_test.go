@@ -0,0 +1,80 @@
+package jsonserialization
+
+import (
+	"testing"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNodeAtPointer(t *testing.T) {
+	source := `{"value":[{"address":{"city":"Redmond"}},{},{},{"address":{"city":"Paris"}}],"a/b":{"m~n":1},"":{"":2},"none":null}`
+	parseNode, err := NewJsonParseNode([]byte(source))
+	require.NoError(t, err)
+
+	tests := []struct {
+		Pointer  string
+		Expected interface{}
+	}{
+		{"/value/3/address/city", "Paris"},
+		{"/value/0/address/city", "Redmond"},
+		{"/a~1b/m~0n", float64(1)},
+		{"//", float64(2)},
+	}
+	for _, test := range tests {
+		t.Run(test.Pointer, func(t *testing.T) {
+			node, err := parseNode.GetNodeAtPointer(test.Pointer)
+			require.NoError(t, err)
+			require.NotNil(t, node)
+			value, err := node.GetRawValue()
+			require.NoError(t, err)
+			switch expected := test.Expected.(type) {
+			case string:
+				assert.Equal(t, expected, *value.(*string))
+			case float64:
+				assert.Equal(t, expected, *value.(*float64))
+			}
+			assert.Equal(t, test.Pointer, node.pointer())
+		})
+	}
+
+	root, err := parseNode.GetNodeAtPointer("")
+	require.NoError(t, err)
+	assert.Same(t, parseNode, root)
+
+	null, err := parseNode.GetNodeAtPointer("/none")
+	require.NoError(t, err)
+	require.NotNil(t, null)
+	value, err := null.GetStringValue()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	for _, missing := range []string{"/missing", "/value/4", "/value/-", "/value/01", "/value/x", "/value/0/address/city/x"} {
+		node, err := parseNode.GetNodeAtPointer(missing)
+		require.NoError(t, err, missing)
+		assert.Nil(t, node, missing)
+	}
+
+	for _, invalid := range []string{"value", "/a~2b", "/a~"} {
+		_, err := parseNode.GetNodeAtPointer(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestGetNodeAtPointerKeepsHooks(t *testing.T) {
+	parseNode, err := NewJsonParseNode([]byte(`{"value":[{"id":"1"}]}`), WithLazyLoading())
+	require.NoError(t, err)
+	called := false
+	require.NoError(t, parseNode.SetOnBeforeAssignFieldValues(func(absser.Parsable) error {
+		called = true
+		return nil
+	}))
+
+	node, err := parseNode.GetNodeAtPointer("/value/0")
+	require.NoError(t, err)
+	require.NotNil(t, node)
+	require.NotNil(t, node.GetOnBeforeAssignFieldValues())
+	require.NoError(t, node.GetOnBeforeAssignFieldValues()(nil))
+	assert.True(t, called)
+}
@@ -33,7 +33,8 @@ func Unmarshal[T absser.Parsable](data []byte, model *T, parser absser.ParsableF
 }
 
 // Marshal JSON-encodes a Parsable value. To enable dirty tracking and better performance, set the
-// DefaultSerializationWriterFactoryInstance for "application/json".
+// DefaultSerializationWriterFactoryInstance for "application/json", for example to a
+// JsonSerializationWriterFactory created with WithDirtyTracking.
 func Marshal(v absser.Parsable) ([]byte, error) {
 	if vRef := reflect.ValueOf(v); !vRef.IsValid() || vRef.IsNil() {
 		return []byte("null"), nil
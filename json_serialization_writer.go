@@ -2,12 +2,16 @@ package jsonserialization
 
 import (
 	"bytes"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
@@ -24,17 +28,57 @@ var buffPool = sync.Pool{
 // JsonSerializationWriter implements SerializationWriter for JSON.
 type JsonSerializationWriter struct {
 	writer                     *bytes.Buffer
-	separatorIndices           []int
 	onBeforeAssignFieldValues  absser.ParsableAction
 	onAfterAssignFieldValues   absser.ParsableAction
 	onStartObjectSerialization absser.ParsableWriter
+	// options holds the settings of the writer, nil for the defaults.
+	options *serializationWriterOptions
+	// objectDepth is the number of objects being written.
+	objectDepth int
+	// objectFrames record the properties written for the objects being written that need them.
+	objectFrames []objectFrame
+	// wholeObjects is set while objects are written with all their properties in dirty tracking mode.
+	wholeObjects int
+	// containers holds the state of the objects and arrays being written, the root first.
+	containers []containerState
+	// afterName is set between a property name and its value.
+	afterName bool
+	// rawPropertyName is set while the next property name is written as it is, without the naming policy.
+	rawPropertyName bool
+	// shared is set once the buffer was returned by GetSerializedContent, so it is never reused.
+	shared bool
+	// output is the writer the content is flushed to, nil when the content is kept in the buffer.
+	output io.Writer
+	// err is the first error writing to the output, returned by every write that follows.
+	err error
+}
+
+// containerState is the state of the root, or of an object or array being written.
+type containerState struct {
+	// first is set until the first member or element of the container is written.
+	first bool
+	// array is set for arrays, whose values are elements rather than member values.
+	array bool
+	// member is set when the last item of an object, or of the root, is a property, named name.
+	member bool
+	name   string
+	// count is the number of elements of an array, or of values of the root written without a key.
+	count int
+}
+
+// objectFrame records the properties written for an object, for the writes that depend on them.
+type objectFrame struct {
+	// depth is the object depth of the properties of the object.
+	depth int
+	keys  map[string]bool
 }
 
 // NewJsonSerializationWriter creates a new instance of the JsonSerializationWriter.
-func NewJsonSerializationWriter() *JsonSerializationWriter {
+func NewJsonSerializationWriter(opts ...JsonSerializationWriterOption) *JsonSerializationWriter {
 	return &JsonSerializationWriter{
-		writer:           buffPool.Get().(*bytes.Buffer),
-		separatorIndices: make([]int, 0),
+		writer:     buffPool.Get().(*bytes.Buffer),
+		containers: []containerState{{first: true}},
+		options:    newSerializationWriterOptions(opts),
 	}
 }
 func (w *JsonSerializationWriter) getWriter() *bytes.Buffer {
@@ -46,12 +90,34 @@ func (w *JsonSerializationWriter) getWriter() *bytes.Buffer {
 }
 func (w *JsonSerializationWriter) writeRawValue(value ...string) {
 	writer := w.getWriter()
+	if w.err != nil || !w.writeValueStart() {
+		return
+	}
 
 	for _, v := range value {
 		writer.WriteString(v)
 	}
 }
 func (w *JsonSerializationWriter) writeStringValue(value string) {
+	w.writeRawValue(quoteJsonString(value))
+}
+
+// writePropertyNameString writes the name of a property as a JSON string, skipping the encoder for the
+// names that need no escaping.
+func writePropertyNameString(writer *bytes.Buffer, key string) {
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c < 0x20 || c == '"' || c == '\\' || c >= utf8.RuneSelf {
+			writer.WriteString(quoteJsonString(key))
+			return
+		}
+	}
+	writer.WriteByte('"')
+	writer.WriteString(key)
+	writer.WriteByte('"')
+}
+
+// quoteJsonString returns the value as a JSON string, quotes included.
+func quoteJsonString(value string) string {
 	builder := &strings.Builder{}
 	// Allocate at least enough space for the string and quotes. However, it's
 	// possible that slightly overallocating may be a better strategy because then
@@ -72,31 +138,138 @@ func (w *JsonSerializationWriter) writeStringValue(value string) {
 	// Note that builder.String() returns a slice referencing the internal memory
 	// of builder. This means it's unsafe to continue holding that reference once
 	// this function exits (for example some conditions where a pool was used to
-	// reduce strings.Builder allocations). We can use it here directly since
-	// writeRawValue calls WriteString on a different buffer which should cause a
-	// copy of the contents. If that's changed though this will need updated.
+	// reduce strings.Builder allocations). We can return it here directly since
+	// the builder is not reused. If that's changed though this will need updated.
 	s := builder.String()
 	// Need to trim off the trailing newline the encoder adds.
-	w.writeRawValue(s[:len(s)-1])
+	return s[:len(s)-1]
 }
 func (w *JsonSerializationWriter) writePropertyName(key string) {
-	w.writeRawValue("\"", key, "\":")
+	if len(w.objectFrames) != 0 {
+		frame := w.objectFrames[len(w.objectFrames)-1]
+		if frame.depth == w.objectDepth {
+			frame.keys[key] = true
+		}
+	}
+	key = w.propertyName(key)
+	if w.err != nil {
+		return
+	}
+	if w.getOptions().structuralValidation {
+		if err := w.validateProperty(key); err != nil {
+			w.err = err
+			return
+		}
+	}
+	w.writeItemStart()
+	state := &w.containers[len(w.containers)-1]
+	state.member, state.name = true, key
+	writer := w.getWriter()
+	writePropertyNameString(writer, key)
+	if w.getOptions().indented {
+		writer.WriteString(": ")
+	} else {
+		writer.WriteByte(':')
+	}
+	w.afterName = true
 }
-func (w *JsonSerializationWriter) writePropertySeparator() {
-	w.separatorIndices = append(w.separatorIndices, w.getWriter().Len())
-	w.writeRawValue(",")
+
+// writeItemStart writes the separator and the line break preceding a member or an element.
+func (w *JsonSerializationWriter) writeItemStart() {
+	w.flushIfDue()
+	state := &w.containers[len(w.containers)-1]
+	if !state.first {
+		w.getWriter().WriteByte(',')
+	}
+	// the first member of the root starts the content
+	if len(w.containers) > 1 || !state.first {
+		w.writeLineBreak()
+	}
+	state.first = false
+}
+
+// writeValueStart writes what precedes a value: nothing for the value of a member, the separator and the
+// line break of an element. It returns false when the value cannot be written.
+func (w *JsonSerializationWriter) writeValueStart() bool {
+	if w.afterName {
+		w.afterName = false
+		return true
+	}
+	if w.getOptions().structuralValidation {
+		if err := w.validateValue(); err != nil {
+			w.err = err
+			return false
+		}
+	}
+	state := &w.containers[len(w.containers)-1]
+	if state.array {
+		w.writeItemStart()
+		state.count++
+	} else if len(w.containers) == 1 {
+		if !state.first {
+			// values written at the root without a key are only separated from the member preceding them
+			w.writeItemStart()
+			state.first = true
+		}
+		state.member = false
+		state.count++
+	}
+	return true
 }
 func (w *JsonSerializationWriter) writeArrayStart() {
 	w.writeRawValue("[")
+	w.containers = append(w.containers, containerState{first: true, array: true})
 }
 func (w *JsonSerializationWriter) writeArrayEnd() {
-	w.writeRawValue("]")
+	w.writeContainerEnd(']')
 }
 func (w *JsonSerializationWriter) writeObjectStart() {
+	w.objectDepth++
 	w.writeRawValue("{")
+	w.containers = append(w.containers, containerState{first: true})
 }
 func (w *JsonSerializationWriter) writeObjectEnd() {
-	w.writeRawValue("}")
+	w.objectDepth--
+	w.writeContainerEnd('}')
+}
+
+// writeContainerEnd ends the object or array being written, on its own line unless it is empty.
+func (w *JsonSerializationWriter) writeContainerEnd(end byte) {
+	if w.err == nil && w.getOptions().structuralValidation {
+		w.err = w.validateContainerEnd()
+	}
+	state := w.containers[len(w.containers)-1]
+	w.containers = w.containers[:len(w.containers)-1]
+	w.afterName = false
+	if w.err != nil {
+		return
+	}
+	if !state.first {
+		w.writeLineBreak()
+	}
+	w.getWriter().WriteByte(end)
+	w.flushIfDue()
+}
+
+// startObjectFrame starts recording the properties of an object when the writes that follow its
+// properties depend on them, see endObjectFrame.
+func (w *JsonSerializationWriter) startObjectFrame(item absser.Parsable) bool {
+	if !w.hasDiscriminator(item) && !w.tracksDirtyValues(item) {
+		return false
+	}
+	w.objectFrames = append(w.objectFrames, objectFrame{depth: w.objectDepth, keys: make(map[string]bool)})
+	return true
+}
+
+// endObjectFrame writes what the properties of the object did not include: the changed values of dirty
+// tracking mode and the discriminator of a registered type.
+func (w *JsonSerializationWriter) endObjectFrame(item absser.Parsable) error {
+	frame := w.objectFrames[len(w.objectFrames)-1]
+	w.objectFrames = w.objectFrames[:len(w.objectFrames)-1]
+	if err := w.writeDirtyValues(item, frame.keys); err != nil {
+		return err
+	}
+	return w.writeDiscriminator(item, frame.keys)
 }
 
 // WriteStringValue writes a String value to underlying the byte array.
@@ -107,10 +280,7 @@ func (w *JsonSerializationWriter) WriteStringValue(key string, value *string) er
 	if value != nil {
 		w.writeStringValue(*value)
 	}
-	if key != "" && value != nil {
-		w.writePropertySeparator()
-	}
-	return nil
+	return w.err
 }
 
 // WriteBoolValue writes a Bool value to underlying the byte array.
@@ -121,10 +291,7 @@ func (w *JsonSerializationWriter) WriteBoolValue(key string, value *bool) error
 	if value != nil {
 		w.writeRawValue(strconv.FormatBool(*value))
 	}
-	if key != "" && value != nil {
-		w.writePropertySeparator()
-	}
-	return nil
+	return w.err
 }
 
 // WriteByteValue writes a Byte value to underlying the byte array.
@@ -133,7 +300,7 @@ func (w *JsonSerializationWriter) WriteByteValue(key string, value *byte) error
 		cast := int64(*value)
 		return w.WriteInt64Value(key, &cast)
 	}
-	return nil
+	return w.err
 }
 
 // WriteInt8Value writes a int8 value to underlying the byte array.
@@ -142,7 +309,7 @@ func (w *JsonSerializationWriter) WriteInt8Value(key string, value *int8) error
 		cast := int64(*value)
 		return w.WriteInt64Value(key, &cast)
 	}
-	return nil
+	return w.err
 }
 
 // WriteInt32Value writes a Int32 value to underlying the byte array.
@@ -151,7 +318,7 @@ func (w *JsonSerializationWriter) WriteInt32Value(key string, value *int32) erro
 		cast := int64(*value)
 		return w.WriteInt64Value(key, &cast)
 	}
-	return nil
+	return w.err
 }
 
 // WriteInt64Value writes a Int64 value to underlying the byte array.
@@ -162,10 +329,7 @@ func (w *JsonSerializationWriter) WriteInt64Value(key string, value *int64) erro
 	if value != nil {
 		w.writeRawValue(strconv.FormatInt(*value, 10))
 	}
-	if key != "" && value != nil {
-		w.writePropertySeparator()
-	}
-	return nil
+	return w.err
 }
 
 // WriteFloat32Value writes a Float32 value to underlying the byte array.
@@ -174,7 +338,7 @@ func (w *JsonSerializationWriter) WriteFloat32Value(key string, value *float32)
 		cast := float64(*value)
 		return w.WriteFloat64Value(key, &cast)
 	}
-	return nil
+	return w.err
 }
 
 // WriteFloat64Value writes a Float64 value to underlying the byte array.
@@ -185,24 +349,48 @@ func (w *JsonSerializationWriter) WriteFloat64Value(key string, value *float64)
 	if value != nil {
 		w.writeRawValue(strconv.FormatFloat(*value, 'f', -1, 64))
 	}
+	return w.err
+}
+
+// WriteBigIntValue writes an arbitrary-precision integer value to underlying the byte array.
+func (w *JsonSerializationWriter) WriteBigIntValue(key string, value *big.Int) error {
 	if key != "" && value != nil {
-		w.writePropertySeparator()
+		w.writePropertyName(key)
 	}
-	return nil
+	if value != nil {
+		w.writeRawValue(value.String())
+	}
+	return w.err
 }
 
-// WriteTimeValue writes a Time value to underlying the byte array.
-func (w *JsonSerializationWriter) WriteTimeValue(key string, value *time.Time) error {
+// WriteBigFloatValue writes an arbitrary-precision floating-point value to underlying the byte array.
+// The shortest representation that reads back to the same value is written.
+func (w *JsonSerializationWriter) WriteBigFloatValue(key string, value *big.Float) error {
+	if value != nil && value.IsInf() {
+		return errors.New("infinite values cannot be written to JSON")
+	}
 	if key != "" && value != nil {
 		w.writePropertyName(key)
 	}
 	if value != nil {
-		w.writeStringValue((*value).Format(time.RFC3339))
+		w.writeRawValue(value.Text('g', -1))
 	}
+	return w.err
+}
+
+// WriteTimeValue writes a Time value to underlying the byte array, in the format set with WithTimeFormat.
+func (w *JsonSerializationWriter) WriteTimeValue(key string, value *time.Time) error {
 	if key != "" && value != nil {
-		w.writePropertySeparator()
+		w.writePropertyName(key)
 	}
-	return nil
+	if value != nil {
+		if text, isNumber := formatTime(*value, w.getOptions()); isNumber {
+			w.writeRawValue(text)
+		} else {
+			w.writeStringValue(text)
+		}
+	}
+	return w.err
 }
 
 // WriteISODurationValue writes a ISODuration value to underlying the byte array.
@@ -213,10 +401,7 @@ func (w *JsonSerializationWriter) WriteISODurationValue(key string, value *absse
 	if value != nil {
 		w.writeStringValue((*value).String())
 	}
-	if key != "" && value != nil {
-		w.writePropertySeparator()
-	}
-	return nil
+	return w.err
 }
 
 // WriteTimeOnlyValue writes a TimeOnly value to underlying the byte array.
@@ -227,10 +412,7 @@ func (w *JsonSerializationWriter) WriteTimeOnlyValue(key string, value *absser.T
 	if value != nil {
 		w.writeStringValue((*value).String())
 	}
-	if key != "" && value != nil {
-		w.writePropertySeparator()
-	}
-	return nil
+	return w.err
 }
 
 // WriteDateOnlyValue writes a DateOnly value to underlying the byte array.
@@ -241,10 +423,7 @@ func (w *JsonSerializationWriter) WriteDateOnlyValue(key string, value *absser.D
 	if value != nil {
 		w.writeStringValue((*value).String())
 	}
-	if key != "" && value != nil {
-		w.writePropertySeparator()
-	}
-	return nil
+	return w.err
 }
 
 // WriteUUIDValue writes a UUID value to underlying the byte array.
@@ -255,24 +434,21 @@ func (w *JsonSerializationWriter) WriteUUIDValue(key string, value *uuid.UUID) e
 	if value != nil {
 		w.writeStringValue((*value).String())
 	}
-	if key != "" && value != nil {
-		w.writePropertySeparator()
-	}
-	return nil
+	return w.err
 }
 
-// WriteByteArrayValue writes a ByteArray value to underlying the byte array.
+// WriteByteArrayValue writes a ByteArray value to underlying the byte array, encoded as set with WithBinaryEncoding.
 func (w *JsonSerializationWriter) WriteByteArrayValue(key string, value []byte) error {
+	if value != nil && w.getOptions().binaryEncoding == BinaryEncodingNumberArray {
+		return w.WriteCollectionOfByteValues(key, value)
+	}
 	if key != "" && value != nil {
 		w.writePropertyName(key)
 	}
 	if value != nil {
-		w.writeStringValue(base64.StdEncoding.EncodeToString(value))
+		w.writeStringValue(encodeBinary(value, w.getOptions().binaryEncoding))
 	}
-	if key != "" && value != nil {
-		w.writePropertySeparator()
-	}
-	return nil
+	return w.err
 }
 
 // WriteObjectValue writes a Parsable value to underlying the byte array.
@@ -284,64 +460,61 @@ func (w *JsonSerializationWriter) WriteObjectValue(key string, item absser.Parsa
 			switch value := untypedNode.(type) {
 			case *absser.UntypedBoolean:
 				w.WriteBoolValue(key, value.GetValue())
-				return nil
+				return w.err
 			case *absser.UntypedFloat:
 				w.WriteFloat32Value(key, value.GetValue())
-				return nil
+				return w.err
 			case *absser.UntypedDouble:
 				w.WriteFloat64Value(key, value.GetValue())
-				return nil
+				return w.err
 			case *absser.UntypedInteger:
 				w.WriteInt32Value(key, value.GetValue())
-				return nil
+				return w.err
 			case *absser.UntypedLong:
 				w.WriteInt64Value(key, value.GetValue())
-				return nil
+				return w.err
 			case *absser.UntypedNull:
 				w.WriteNullValue(key)
-				return nil
+				return w.err
 			case *absser.UntypedString:
 				w.WriteStringValue(key, value.GetValue())
-				return nil
-			case *absser.UntypedObject:
-				if key != "" {
-					w.writePropertyName(key)
+				return w.err
+			case *absser.UntypedNode:
+				// numbers that do not fit the typed untyped nodes are held by the base node
+				switch raw := value.GetValue().(type) {
+				case *big.Int:
+					return w.WriteBigIntValue(key, raw)
+				case *big.Float:
+					return w.WriteBigFloatValue(key, raw)
+				case json.Number:
+					return w.writeNumberText(key, raw)
+				case nil:
+					return w.WriteNullValue(key)
+				default:
+					return w.WriteAnyValue(key, raw)
 				}
+			case *OrderedUntypedObject:
+				return w.writeUntypedObject(key, value.GetValue(), value.GetKeys())
+			case *absser.UntypedObject:
 				properties := value.GetValue()
-				if properties != nil {
-					w.writeObjectStart()
-					for objectKey, val := range properties {
-						err := w.WriteObjectValue(objectKey, val)
-						if err != nil {
-							return err
-						}
-					}
-					w.writeObjectEnd()
-					if key != "" {
-						w.writePropertySeparator()
-					}
-				}
-				return nil
+				return w.writeUntypedObject(key, properties, orderedKeys(nil, properties))
 			case *absser.UntypedArray:
+				values := value.GetValue()
+				if values == nil {
+					return w.WriteNullValue(key)
+				}
 				if key != "" {
 					w.writePropertyName(key)
 				}
-				values := value.GetValue()
-				if values != nil {
-					w.writeArrayStart()
-					for _, val := range values {
-						err := w.WriteObjectValue("", val)
-						if err != nil {
-							return err
-						}
-						w.writePropertySeparator()
+				w.writeArrayStart()
+				for _, val := range values {
+					err := w.WriteObjectValue("", val)
+					if err != nil {
+						return err
 					}
-					w.writeArrayEnd()
-				}
-				if key != "" {
-					w.writePropertySeparator()
 				}
-				return nil
+				w.writeArrayEnd()
+				return w.err
 			}
 		}
 
@@ -350,15 +523,19 @@ func (w *JsonSerializationWriter) WriteObjectValue(key string, item absser.Parsa
 		}
 		abstractions.InvokeParsableAction(w.GetOnBeforeSerialization(), item)
 		_, isComposedTypeWrapper := item.(absser.ComposedTypeWrapper)
+		tracked := false
 		if !isComposedTypeWrapper {
 			w.writeObjectStart()
+			tracked = w.startObjectFrame(item)
 		}
 		if item != nil {
 			err := abstractions.InvokeParsableWriter(w.GetOnStartObjectSerialization(), item, w)
 			if err != nil {
 				return err
 			}
+			restore := w.startDirtyTracking(item)
 			err = item.Serialize(w)
+			restore()
 
 			abstractions.InvokeParsableAction(w.GetOnAfterObjectSerialization(), item)
 			if err != nil {
@@ -379,14 +556,49 @@ func (w *JsonSerializationWriter) WriteObjectValue(key string, item absser.Parsa
 			abstractions.InvokeParsableAction(w.GetOnAfterObjectSerialization(), additionalValue)
 		}
 
+		if tracked {
+			if err := w.endObjectFrame(item); err != nil {
+				return err
+			}
+		}
 		if !isComposedTypeWrapper {
 			w.writeObjectEnd()
 		}
-		if key != "" {
-			w.writePropertySeparator()
+	}
+	return w.err
+}
+
+// writeUntypedObject writes the properties of an untyped object in the order of the keys.
+func (w *JsonSerializationWriter) writeUntypedObject(key string, properties map[string]absser.UntypedNodeable, keys []string) error {
+	if properties == nil {
+		return w.WriteNullValue(key)
+	}
+	if key != "" {
+		w.writePropertyName(key)
+	}
+	w.writeObjectStart()
+	for _, objectKey := range keys {
+		w.rawPropertyName = true
+		err := w.WriteObjectValue(objectKey, properties[objectKey])
+		w.rawPropertyName = false
+		if err != nil {
+			return err
 		}
 	}
-	return nil
+	w.writeObjectEnd()
+	return w.err
+}
+
+// writeNumberText writes a number as the text it was read from.
+func (w *JsonSerializationWriter) writeNumberText(key string, value json.Number) error {
+	if !isValidNumber(value.String()) {
+		return fmt.Errorf("invalid number %q", value)
+	}
+	if key != "" {
+		w.writePropertyName(key)
+	}
+	w.writeRawValue(value.String())
+	return w.err
 }
 
 // WriteCollectionOfObjectValues writes a collection of Parsable values to underlying the byte array.
@@ -396,6 +608,9 @@ func (w *JsonSerializationWriter) WriteCollectionOfObjectValues(key string, coll
 			w.writePropertyName(key)
 		}
 		w.writeArrayStart()
+		// PATCH requests replace collections, their objects are written whole
+		w.wholeObjects++
+		defer func() { w.wholeObjects-- }()
 		for _, item := range collection {
 			if item != nil {
 				err := w.WriteObjectValue("", item)
@@ -408,15 +623,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfObjectValues(key string, coll
 					return err
 				}
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfStringValues writes a collection of String values to underlying the byte array.
@@ -431,15 +642,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfStringValues(key string, coll
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfInt32Values writes a collection of Int32 values to underlying the byte array.
@@ -454,15 +661,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfInt32Values(key string, colle
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfInt64Values writes a collection of Int64 values to underlying the byte array.
@@ -477,15 +680,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfInt64Values(key string, colle
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfFloat32Values writes a collection of Float32 values to underlying the byte array.
@@ -500,15 +699,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfFloat32Values(key string, col
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfFloat64Values writes a collection of Float64 values to underlying the byte array.
@@ -523,15 +718,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfFloat64Values(key string, col
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfTimeValues writes a collection of Time values to underlying the byte array.
@@ -546,15 +737,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfTimeValues(key string, collec
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfISODurationValues writes a collection of ISODuration values to underlying the byte array.
@@ -569,15 +756,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfISODurationValues(key string,
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfTimeOnlyValues writes a collection of TimeOnly values to underlying the byte array.
@@ -592,15 +775,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfTimeOnlyValues(key string, co
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfDateOnlyValues writes a collection of DateOnly values to underlying the byte array.
@@ -615,15 +794,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfDateOnlyValues(key string, co
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfUUIDValues writes a collection of UUID values to underlying the byte array.
@@ -638,15 +813,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfUUIDValues(key string, collec
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfBoolValues writes a collection of Bool values to underlying the byte array.
@@ -661,15 +832,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfBoolValues(key string, collec
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfByteValues writes a collection of Byte values to underlying the byte array.
@@ -684,15 +851,11 @@ func (w *JsonSerializationWriter) WriteCollectionOfByteValues(key string, collec
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 // WriteCollectionOfInt8Values writes a collection of int8 values to underlying the byte array.
@@ -707,42 +870,53 @@ func (w *JsonSerializationWriter) WriteCollectionOfInt8Values(key string, collec
 			if err != nil {
 				return err
 			}
-			w.writePropertySeparator()
 		}
 
 		w.writeArrayEnd()
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
-// GetSerializedContent returns the resulting byte array from the serialization writer.
+// GetSerializedContent returns the resulting byte array from the serialization writer. The content is
+// not copied: the writer stops reusing its buffer once it was returned, so the content stays valid after
+// Reset and Close. Writers bound to an output flush the content left instead and return no content.
 func (w *JsonSerializationWriter) GetSerializedContent() ([]byte, error) {
-	trimmed := w.getWriter().Bytes()
-	buffLen := len(trimmed)
-
-	for i := len(w.separatorIndices) - 1; i >= 0; i-- {
-		idx := w.separatorIndices[i]
+	if w.output != nil {
+		return nil, w.Flush()
+	}
+	if w.getOptions().canonical {
+		return canonicalizeJson(w.getWriter().Bytes())
+	}
+	w.shared = true
+	return w.getWriter().Bytes(), nil
+}
 
-		if idx == buffLen-1 {
-			trimmed = trimmed[:idx]
-		} else if trimmed[idx+1] == byte(']') || trimmed[idx+1] == byte('}') {
-			trimmed = append(trimmed[:idx], trimmed[idx+1:]...)
+// WriteTo writes the content GetSerializedContent returns to the output, without copying it.
+func (w *JsonSerializationWriter) WriteTo(output io.Writer) (int64, error) {
+	if w.output != nil {
+		return 0, errors.New("the content is written to the output the writer is bound to")
+	}
+	content := w.getWriter().Bytes()
+	if w.getOptions().canonical {
+		var err error
+		if content, err = canonicalizeJson(content); err != nil {
+			return 0, err
 		}
 	}
-
-	trimmedCopy := make([]byte, len(trimmed))
-	copy(trimmedCopy, trimmed)
-
-	return trimmedCopy, nil
+	written, err := output.Write(content)
+	return int64(written), err
 }
 
 // WriteAnyValue an unknown value as a parameter.
 func (w *JsonSerializationWriter) WriteAnyValue(key string, value interface{}) error {
 	if value != nil {
-		body, err := json.Marshal(value)
+		var body []byte
+		var err error
+		if options := w.getOptions(); options.indented {
+			body, err = json.MarshalIndent(value, w.lineBreak()[1:], options.indent)
+		} else {
+			body, err = json.Marshal(value)
+		}
 		if err != nil {
 			return err
 		}
@@ -752,11 +926,8 @@ func (w *JsonSerializationWriter) WriteAnyValue(key string, value interface{}) e
 
 		w.writeRawValue(string(body))
 
-		if key != "" {
-			w.writePropertySeparator()
-		}
 	}
-	return nil
+	return w.err
 }
 
 func (w *JsonSerializationWriter) WriteNullValue(key string) error {
@@ -766,11 +937,7 @@ func (w *JsonSerializationWriter) WriteNullValue(key string) error {
 
 	w.writeRawValue("null")
 
-	if key != "" {
-		w.writePropertySeparator()
-	}
-
-	return nil
+	return w.err
 }
 
 func (w *JsonSerializationWriter) GetOnBeforeSerialization() absser.ParsableAction {
@@ -801,10 +968,14 @@ func (w *JsonSerializationWriter) SetOnStartObjectSerialization(writer absser.Pa
 }
 
 // WriteAdditionalData writes additional data to underlying the byte array.
+// Entries read in round-trip mode are written in the order of the payload, the others sorted by key.
 func (w *JsonSerializationWriter) WriteAdditionalData(value map[string]interface{}) error {
 	var err error
 	if len(value) != 0 {
-		for key, input := range value {
+		for _, key := range orderedKeys(additionalDataOrder(value), value) {
+			input := value[key]
+			// additional data is written with the names it was read with
+			w.rawPropertyName = true
 			switch value := input.(type) {
 			case absser.Parsable:
 				err = w.WriteObjectValue(key, value)
@@ -815,7 +986,7 @@ func (w *JsonSerializationWriter) WriteAdditionalData(value map[string]interface
 			case []bool:
 				err = w.WriteCollectionOfBoolValues(key, value)
 			case []byte:
-				err = w.WriteCollectionOfByteValues(key, value)
+				err = w.WriteByteArrayValue(key, value)
 			case []int8:
 				err = w.WriteCollectionOfInt8Values(key, value)
 			case []int32:
@@ -888,34 +1059,67 @@ func (w *JsonSerializationWriter) WriteAdditionalData(value map[string]interface
 				err = w.WriteDateOnlyValue(key, value)
 			case absser.DateOnly:
 				err = w.WriteDateOnlyValue(key, &value)
+			case *big.Int:
+				err = w.WriteBigIntValue(key, value)
+			case *big.Float:
+				err = w.WriteBigFloatValue(key, value)
+			case json.Number:
+				err = w.writeNumberText(key, value)
+			case *json.Number:
+				if value != nil {
+					err = w.writeNumberText(key, *value)
+				}
 			case absser.UntypedNodeable:
 				err = w.WriteObjectValue(key, value)
 			default:
 				err = w.WriteAnyValue(key, &value)
 			}
+			w.rawPropertyName = false
+			if err != nil {
+				return err
+			}
 		}
 	}
-	return err
+	return w.err
 }
 
 // Reset sets the internal buffer to empty, allowing the writer to be reused.
 func (w *JsonSerializationWriter) Reset() error {
-	w.getWriter().Reset()
-	w.separatorIndices = w.separatorIndices[:0]
+	buffer := w.getWriter()
+	if w.shared {
+		// the content returned by GetSerializedContent stays with the caller
+		buffer = buffPool.Get().(*bytes.Buffer)
+		w.writer, w.shared = buffer, false
+	}
+	buffer.Reset()
+	w.containers = append(w.containers[:0], containerState{first: true})
+	w.afterName = false
+	w.rawPropertyName = false
+	w.objectDepth = 0
+	w.objectFrames = w.objectFrames[:0]
+	w.wholeObjects = 0
+	w.err = nil
 	return nil
 }
 
 // Close relases the internal buffer. Subsequent calls to the writer will panic.
+// Writers bound to an output flush the content left first and return the error writing it.
 func (w *JsonSerializationWriter) Close() error {
 	if w.writer == nil {
 		return nil
 	}
+	var err error
+	if w.output != nil {
+		err = w.Flush()
+	}
 
-	w.writer.Reset()
-	buffPool.Put(w.writer)
+	if !w.shared {
+		w.writer.Reset()
+		buffPool.Put(w.writer)
+	}
 
 	w.writer = nil
-	w.separatorIndices = nil
+	w.containers = nil
 
-	return nil
+	return err
 }
@@ -8,11 +8,15 @@ import (
 
 // JsonSerializationWriterFactory implements SerializationWriterFactory for JSON.
 type JsonSerializationWriterFactory struct {
+	options []JsonSerializationWriterOption
 }
 
-// NewJsonSerializationWriterFactory creates a new instance of the JsonSerializationWriterFactory.
-func NewJsonSerializationWriterFactory() *JsonSerializationWriterFactory {
-	return &JsonSerializationWriterFactory{}
+// NewJsonSerializationWriterFactory creates a new instance of the JsonSerializationWriterFactory, the options
+// apply to every writer it creates.
+func NewJsonSerializationWriterFactory(opts ...JsonSerializationWriterOption) *JsonSerializationWriterFactory {
+	return &JsonSerializationWriterFactory{
+		options: opts,
+	}
 }
 
 // GetValidContentType returns the valid content type for the SerializationWriterFactoryRegistry
@@ -30,6 +34,6 @@ func (f *JsonSerializationWriterFactory) GetSerializationWriter(contentType stri
 	} else if contentType != validType {
 		return nil, errors.New("contentType is not valid")
 	} else {
-		return NewJsonSerializationWriter(), nil
+		return NewJsonSerializationWriter(f.options...), nil
 	}
 }
@@ -2,6 +2,7 @@ package jsonserialization
 
 import (
 	"testing"
+	"time"
 
 	assert "github.com/stretchr/testify/assert"
 
@@ -12,3 +13,35 @@ func TestJsonSerializationFactoryWriterHonoursInterface(t *testing.T) {
 	instance := NewJsonSerializationWriterFactory()
 	assert.Implements(t, (*absser.SerializationWriterFactory)(nil), instance)
 }
+
+func TestJsonSerializationWriterFactoryAppliesOptions(t *testing.T) {
+	factory := NewJsonSerializationWriterFactory(WithTimeFormat(TimeFormatUnixMilliseconds))
+	writer, err := factory.GetSerializationWriter("application/json")
+	assert.Nil(t, err)
+	value := time.UnixMilli(1689144864123)
+	assert.Nil(t, writer.WriteTimeValue("key", &value))
+	result, err := writer.GetSerializedContent()
+	assert.Nil(t, err)
+	assert.Equal(t, `"key":1689144864123`, string(result))
+}
+
+func TestJsonSerializationWriterFactoryAppliesIndent(t *testing.T) {
+	factory := NewJsonSerializationWriterFactory(WithIndent("", "\t"))
+	writer, err := factory.GetSerializationWriter("application/json")
+	assert.Nil(t, err)
+	assert.Nil(t, writer.WriteCollectionOfStringValues("", []string{"a", "b"}))
+	result, err := writer.GetSerializedContent()
+	assert.Nil(t, err)
+	assert.Equal(t, "[\n\t\"a\",\n\t\"b\"\n]", string(result))
+}
+
+func TestJsonSerializationWriterFactoryAppliesNamingPolicy(t *testing.T) {
+	factory := NewJsonSerializationWriterFactory(WithNamingPolicy(PascalCaseNamingPolicy))
+	writer, err := factory.GetSerializationWriter("application/json")
+	assert.Nil(t, err)
+	value := "Montreal"
+	assert.Nil(t, writer.WriteStringValue("officeLocation", &value))
+	result, err := writer.GetSerializedContent()
+	assert.Nil(t, err)
+	assert.Equal(t, `"OfficeLocation":"Montreal"`, string(result))
+}
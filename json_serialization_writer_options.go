@@ -0,0 +1,54 @@
+package jsonserialization
+
+// JsonSerializationWriterOption configures how values are written. Options are passed to
+// NewJsonSerializationWriter or NewJsonSerializationWriterFactory.
+type JsonSerializationWriterOption interface {
+	applyToSerializationWriter(options *serializationWriterOptions)
+}
+
+// serializationWriterOptions holds the settings of a serialization writer.
+type serializationWriterOptions struct {
+	timeFormat           TimeFormat
+	utcTimes             bool
+	binaryEncoding       BinaryEncoding
+	discriminators       *DiscriminatorRegistry
+	dirtyTracking        bool
+	indented             bool
+	indentPrefix         string
+	indent               string
+	canonical            bool
+	streamBufferSize     int
+	structuralValidation bool
+	namingPolicy         *namingPolicy
+}
+
+// defaultSerializationWriterOptions is used by writers that were not created with any option.
+var defaultSerializationWriterOptions = serializationWriterOptions{}
+
+type serializationWriterOptionFunc func(options *serializationWriterOptions)
+
+func (f serializationWriterOptionFunc) applyToSerializationWriter(options *serializationWriterOptions) {
+	f(options)
+}
+
+// newSerializationWriterOptions returns the settings resulting from the options, nil when there is none.
+func newSerializationWriterOptions(opts []JsonSerializationWriterOption) *serializationWriterOptions {
+	if len(opts) == 0 {
+		return nil
+	}
+	options := defaultSerializationWriterOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyToSerializationWriter(&options)
+		}
+	}
+	return &options
+}
+
+// getOptions returns the settings of the writer.
+func (w *JsonSerializationWriter) getOptions() *serializationWriterOptions {
+	if w.options == nil {
+		return &defaultSerializationWriterOptions
+	}
+	return w.options
+}
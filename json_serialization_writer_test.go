@@ -1,8 +1,10 @@
 package jsonserialization
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
@@ -56,6 +58,49 @@ func TestWriteTimeValue(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("\"key\":%q", value.Format(time.RFC3339)), string(result[:]))
 }
 
+func TestWriteTimeValueFormats(t *testing.T) {
+	value := time.Date(2023, 7, 12, 9, 54, 24, 123400000, time.FixedZone("", 3*60*60))
+	tests := []struct {
+		Options  []JsonSerializationWriterOption
+		Expected string
+	}{
+		{nil, `"2023-07-12T09:54:24+03:00"`},
+		{[]JsonSerializationWriterOption{WithTimeFormat(TimeFormatRFC3339Nano)}, `"2023-07-12T09:54:24.1234+03:00"`},
+		{[]JsonSerializationWriterOption{WithTimeFormat(TimeFormatRFC3339Milliseconds)}, `"2023-07-12T09:54:24.123+03:00"`},
+		{[]JsonSerializationWriterOption{WithTimeFormat(TimeFormatRFC3339Nano), WithUTCTimes()}, `"2023-07-12T06:54:24.1234Z"`},
+		{[]JsonSerializationWriterOption{WithTimeFormat(TimeFormatUnixSeconds)}, `1689144864.1234`},
+		{[]JsonSerializationWriterOption{WithTimeFormat(TimeFormatUnixMilliseconds)}, `1689144864123`},
+	}
+	for _, test := range tests {
+		t.Run(test.Expected, func(t *testing.T) {
+			serializer := NewJsonSerializationWriter(test.Options...)
+			require.NoError(t, serializer.WriteTimeValue("key", &value))
+			require.NoError(t, serializer.WriteCollectionOfTimeValues("values", []time.Time{value}))
+			require.NoError(t, serializer.WriteAdditionalData(map[string]interface{}{"additional": value}))
+			result, err := serializer.GetSerializedContent()
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf(`"key":%s,"values":[%[1]s],"additional":%[1]s`, test.Expected), string(result))
+		})
+	}
+}
+
+func TestWriteTimeValueAsUnixSeconds(t *testing.T) {
+	serializer := NewJsonSerializationWriter(WithTimeFormat(TimeFormatUnixSeconds))
+	values := []time.Time{time.Unix(0, 0), time.Unix(-2, 500000000), time.Unix(-1, 750000000), time.Unix(1, 0)}
+	require.NoError(t, serializer.WriteCollectionOfTimeValues("", values))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `[0,-1.5,-0.25,1]`, string(result))
+
+	parseNode, err := NewJsonParseNode(result, WithTimeLayouts(TimeLayoutUnixSeconds))
+	require.NoError(t, err)
+	parsed, err := parseNode.GetCollectionOfPrimitiveValues("time")
+	require.NoError(t, err)
+	for i, value := range values {
+		assert.True(t, value.Equal(*parsed[i].(*time.Time)), value.String())
+	}
+}
+
 func TestWriteISODurationValue(t *testing.T) {
 	serializer := NewJsonSerializationWriter()
 	value := absser.NewDuration(1, 0, 2, 3, 4, 5, 6)
@@ -121,6 +166,73 @@ func TestWriteByteArrayValue(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("\"key\":\"%s\"", expected), string(result[:]))
 }
 
+func TestWriteByteArrayValueEncodings(t *testing.T) {
+	value := []byte{0xfb, 0xff, 0x3e, 0x01}
+	tests := []struct {
+		Encoding BinaryEncoding
+		Expected string
+	}{
+		{BinaryEncodingBase64, `"+/8+AQ=="`},
+		{BinaryEncodingBase64URL, `"-_8-AQ=="`},
+		{BinaryEncodingRawBase64, `"+/8+AQ"`},
+		{BinaryEncodingRawBase64URL, `"-_8-AQ"`},
+		{BinaryEncodingHex, `"fbff3e01"`},
+		{BinaryEncodingNumberArray, `[251,255,62,1]`},
+	}
+	for _, test := range tests {
+		t.Run(test.Expected, func(t *testing.T) {
+			serializer := NewJsonSerializationWriter(WithBinaryEncoding(test.Encoding))
+			require.NoError(t, serializer.WriteByteArrayValue("key", value))
+			require.NoError(t, serializer.WriteAdditionalData(map[string]interface{}{"additional": value}))
+			result, err := serializer.GetSerializedContent()
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf(`"key":%s,"additional":%[1]s`, test.Expected), string(result))
+
+			// the payload reads back with the same option
+			parseNode, err := NewJsonParseNode([]byte("{"+string(result)+"}"), WithBinaryEncoding(test.Encoding))
+			require.NoError(t, err)
+			child, err := parseNode.GetChildNode("key")
+			require.NoError(t, err)
+			decoded, err := child.GetByteArrayValue()
+			require.NoError(t, err)
+			assert.Equal(t, value, decoded)
+		})
+	}
+}
+
+func TestWriteBigNumberValues(t *testing.T) {
+	serializer := NewJsonSerializationWriter()
+	bigInt, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	serializer.WriteBigIntValue("int", bigInt)
+	bigFloat, _, _ := big.ParseFloat("1.5e400", 10, 64, big.ToNearestEven)
+	serializer.WriteBigFloatValue("float", bigFloat)
+	result, err := serializer.GetSerializedContent()
+	assert.Nil(t, err)
+	assert.Equal(t, "\"int\":123456789012345678901234567890,\"float\":1.5e+400", string(result[:]))
+
+	err = serializer.WriteBigFloatValue("inf", new(big.Float).SetInf(false))
+	assert.Error(t, err)
+}
+
+func TestBigNumbersRoundTripThroughAdditionalData(t *testing.T) {
+	source := `{"id":"1","counter":123456789012345678901234567890,"ratio":1.5e400,"large":9007199254740993,"location":{"big":98765432109876543210}}`
+	parseNode, err := NewJsonParseNode([]byte(source))
+	require.NoError(t, err)
+	parsable, err := parseNode.GetObjectValue(internal.UntypedTestEntityDiscriminator)
+	require.NoError(t, err)
+
+	serializer := NewJsonSerializationWriter()
+	err = serializer.WriteObjectValue("", parsable)
+	require.NoError(t, err)
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	stringResult := string(result)
+	assert.Contains(t, stringResult, "\"counter\":123456789012345678901234567890")
+	assert.Contains(t, stringResult, "\"ratio\":1.5e+400")
+	assert.Contains(t, stringResult, "\"large\":9007199254740993")
+	assert.Contains(t, stringResult, "\"location\":{\"big\":98765432109876543210}")
+}
+
 func TestDoubleEscapeFailure(t *testing.T) {
 	serializer := NewJsonSerializationWriter()
 	value := "W/\"CQAAABYAAAAs+XSiyjZdS4Rhtwk0v1pGAAC5bsJ2\""
@@ -161,6 +273,72 @@ func TestClose(t *testing.T) {
 	})
 }
 
+func TestGetSerializedContentIsNotCopied(t *testing.T) {
+	serializer := NewJsonSerializationWriter()
+	value := "value"
+	require.NoError(t, serializer.WriteStringValue("key", &value))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	again, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Same(t, &result[0], &again[0])
+
+	require.NoError(t, serializer.Reset())
+	other := "other"
+	require.NoError(t, serializer.WriteStringValue("key", &other))
+	require.NoError(t, serializer.Close())
+	for i := 0; i < 10; i++ {
+		reused := NewJsonSerializationWriter()
+		require.NoError(t, reused.WriteStringValue("overwritten", &other))
+		require.NoError(t, reused.Close())
+	}
+	assert.Equal(t, `"key":"value"`, string(result))
+}
+
+func TestWriteTo(t *testing.T) {
+	serializer := NewJsonSerializationWriter()
+	require.NoError(t, serializer.WriteCollectionOfStringValues("names", []string{"a", "b"}))
+	var output bytes.Buffer
+	written, err := serializer.WriteTo(&output)
+	require.NoError(t, err)
+	assert.Equal(t, int64(output.Len()), written)
+	assert.Equal(t, `"names":["a","b"]`, output.String())
+
+	canonical := NewJsonSerializationWriter(WithCanonicalJson())
+	require.NoError(t, canonical.WriteObjectValue("", absser.NewUntypedObject(map[string]absser.UntypedNodeable{
+		"b": absser.NewUntypedLong(1),
+		"a": absser.NewUntypedNull(),
+	})))
+	output.Reset()
+	_, err = canonical.WriteTo(&output)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":null,"b":1}`, output.String())
+
+	bound, err := NewJsonSerializationWriterTo(&output)
+	require.NoError(t, err)
+	_, err = bound.WriteTo(&output)
+	assert.Error(t, err)
+}
+
+func TestWriteSeparatorsAsValuesAreWritten(t *testing.T) {
+	serializer := NewJsonSerializationWriter()
+	id := "1"
+	entity := internal.NewTestEntity()
+	entity.SetId(&id)
+	require.NoError(t, serializer.WriteObjectValue("object", absser.NewUntypedObject(map[string]absser.UntypedNodeable{
+		"empty":   absser.NewUntypedObject(map[string]absser.UntypedNodeable{}),
+		"list":    absser.NewUntypedArray([]absser.UntypedNodeable{absser.NewUntypedArray([]absser.UntypedNodeable{}), nil, absser.NewUntypedNull()}),
+		"missing": absser.NewUntypedArray(nil),
+		"nothing": absser.NewUntypedObject(nil),
+		"numbers": absser.NewUntypedArray([]absser.UntypedNodeable{absser.NewUntypedLong(1), absser.NewUntypedLong(2)}),
+		"z":       absser.NewUntypedString(","),
+	})))
+	require.NoError(t, serializer.WriteCollectionOfObjectValues("entities", []absser.Parsable{nil, entity, internal.NewTestEntity()}))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `"object":{"empty":{},"list":[[],null],"missing":null,"nothing":null,"numbers":[1,2],"z":","},"entities":[null,{"id":"1"},{}]`, string(result))
+}
+
 func TestJsonSerializationWriterHonoursInterface(t *testing.T) {
 	instance := NewJsonSerializationWriter()
 	assert.Implements(t, (*absser.SerializationWriter)(nil), instance)
@@ -554,6 +732,41 @@ func TestJsonSerializationWriter_WriteEnumValue(t *testing.T) {
 	assert.Contains(t, converted, "\"sensitivity\":\"normal\"")
 }
 
+func TestJsonSerializationWriter_WriteFlagsEnumValues(t *testing.T) {
+	serializer := NewJsonSerializationWriter()
+	permissions := internal.SHARE_TESTPERMISSIONS | internal.READ_TESTPERMISSIONS
+	sensitivity := internal.PRIVATE_SENSITIVITY
+	require.NoError(t, serializer.WriteEnumValue("permissions", permissions))
+	require.NoError(t, serializer.WriteEnumValue("sensitivity", sensitivity))
+	require.NoError(t, serializer.WriteEnumValue("none", (*internal.TestPermissions)(nil)))
+	require.NoError(t, serializer.WriteCollectionOfEnumValues("collection", []fmt.Stringer{internal.WRITE_TESTPERMISSIONS | internal.DELETE_TESTPERMISSIONS, nil}))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `"permissions":"read,share","sensitivity":"private","collection":["write,delete",null]`, string(result))
+}
+
+func TestJsonSerializationWriter_WriteCanonicalEnumValue(t *testing.T) {
+	serializer := NewJsonSerializationWriter()
+	require.NoError(t, serializer.WriteEnumValue("canonical", stringer(" read , write ,read,, write")))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `"canonical":"read,write"`, string(result))
+
+	parseNode, err := NewJsonParseNode([]byte("{" + string(result) + "}"))
+	require.NoError(t, err)
+	child, err := parseNode.GetChildNode("canonical")
+	require.NoError(t, err)
+	value, err := child.GetEnumValue(internal.ParseTestPermissions)
+	require.NoError(t, err)
+	assert.Equal(t, internal.READ_TESTPERMISSIONS|internal.WRITE_TESTPERMISSIONS, *value.(*internal.TestPermissions))
+}
+
+type stringer string
+
+func (s stringer) String() string {
+	return string(s)
+}
+
 func TestJsonSerializationWriter(t *testing.T) {
 	serializer := NewJsonSerializationWriter()
 	countBefore := 0
@@ -670,3 +883,194 @@ func TestWriteUntypedJson(t *testing.T) {
 type TestStruct struct {
 	Key string `json:"key"`
 }
+
+func TestLosslessRoundTripOfUnknownMembers(t *testing.T) {
+	source := `{"id":"1","zeta":1.0,"title":"t","alpha":{"b":2.50,"a":[1e2,null,"x",12345678901234567890.5]},"mid":null,"location":{"z":1,"y":10.00,"x":{"d":-0,"c":true}}}`
+	parseNode, err := NewJsonParseNode([]byte(source), WithLosslessRoundTrip())
+	require.NoError(t, err)
+	parsable, err := parseNode.GetObjectValue(internal.UntypedTestEntityDiscriminator)
+	require.NoError(t, err)
+
+	entity := parsable.(*internal.UntypedTestEntity)
+	location, ok := entity.GetLocation().(*OrderedUntypedObject)
+	require.True(t, ok)
+	assert.Equal(t, []string{"z", "y", "x"}, location.GetKeys())
+	assert.Len(t, entity.GetAdditionalData(), 3)
+
+	serializer := NewJsonSerializationWriter()
+	err = serializer.WriteObjectValue("", parsable)
+	require.NoError(t, err)
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1","title":"t","location":{"z":1,"y":10.00,"x":{"d":-0,"c":true}},"zeta":1.0,"alpha":{"b":2.50,"a":[1e2,null,"x",12345678901234567890.5]},"mid":null}`, string(result))
+}
+
+func TestWriteAdditionalDataOrder(t *testing.T) {
+	entity := internal.NewUntypedTestEntity()
+	entity.SetAdditionalData(map[string]interface{}{
+		"c":                "3",
+		"b":                json.Number("2.0"),
+		"a":                true,
+		"\xffmember-order": "kept",
+	})
+	serializer := NewJsonSerializationWriter()
+	err := serializer.WriteObjectValue("", entity)
+	require.NoError(t, err)
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":true,\"b\":2.0,\"c\":\"3\",\"\ufffdmember-order\":\"kept\"}", string(result))
+
+	err = serializer.WriteAdditionalData(map[string]interface{}{"n": json.Number("1x")})
+	assert.Error(t, err)
+}
+
+func TestLosslessRoundTripKeepsAdditionalDataOrderOfModels(t *testing.T) {
+	source := `{"zeta":"z","id":"1","alpha":2,"title":"t","mid":null,"beta":[true]}`
+	parseNode, err := NewJsonParseNode([]byte(source), WithLosslessRoundTrip())
+	require.NoError(t, err)
+	parsable, err := parseNode.GetObjectValue(internal.UntypedTestEntityDiscriminator)
+	require.NoError(t, err)
+
+	entity := parsable.(*internal.UntypedTestEntity)
+	additionalData := entity.GetAdditionalData()
+	delete(additionalData, "alpha")
+	additionalData["added"] = "a"
+	serializer := NewJsonSerializationWriter()
+	require.NoError(t, serializer.WriteObjectValue("", entity))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1","title":"t","zeta":"z","mid":null,"beta":[true],"added":"a"}`, string(result))
+
+	copied := make(map[string]interface{}, len(additionalData))
+	for key, value := range additionalData {
+		copied[key] = value
+	}
+	serializer = NewJsonSerializationWriter()
+	require.NoError(t, serializer.WriteAdditionalData(copied))
+	result, err = serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `"added":"a","beta":[true],"mid":null,"zeta":"z"`, string(result))
+}
+
+func TestLosslessRoundTripAdditionalDataHoldsOnlyMembers(t *testing.T) {
+	source := `{"id":"1","zeta":1,"alpha":true}`
+	parseNode, err := NewJsonParseNode([]byte(source), WithLosslessRoundTrip())
+	require.NoError(t, err)
+	parsable, err := parseNode.GetObjectValue(internal.CreateTestEntityFromDiscriminator)
+	require.NoError(t, err)
+
+	additionalData := parsable.(*internal.TestEntity).GetAdditionalData()
+	keys := make([]string, 0, len(additionalData))
+	for key := range additionalData {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{"zeta", "alpha"}, keys)
+}
+
+func TestWriteUntypedObjectInOrder(t *testing.T) {
+	serializer := NewJsonSerializationWriter()
+	properties := map[string]absser.UntypedNodeable{
+		"b": absser.NewUntypedString("2"),
+		"a": absser.NewUntypedNode(json.Number("1.50")),
+		"c": absser.NewUntypedNull(),
+	}
+	err := serializer.WriteObjectValue("ordered", NewOrderedUntypedObject([]string{"b", "a"}, properties))
+	require.NoError(t, err)
+	err = serializer.WriteObjectValue("sorted", absser.NewUntypedObject(properties))
+	require.NoError(t, err)
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `"ordered":{"b":"2","a":1.50,"c":null},"sorted":{"a":1.50,"b":"2","c":null}`, string(result))
+}
+
+func TestWriteIndentedJson(t *testing.T) {
+	source := `{"id":"1","title":"t","location":{"z":1,"empty":{},"list":[],"nested":[[1,2],[{"a":null}]]},"keywords":["a",{"b":true}],"extra":{"c":[1.50]},"last":null}`
+	parseNode, err := NewJsonParseNode([]byte(source), WithLosslessRoundTrip())
+	require.NoError(t, err)
+	parsable, err := parseNode.GetObjectValue(internal.UntypedTestEntityDiscriminator)
+	require.NoError(t, err)
+
+	serializer := NewJsonSerializationWriter(WithIndent("", "  "))
+	require.NoError(t, serializer.WriteObjectValue("", parsable))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "id": "1",
+  "title": "t",
+  "location": {
+    "z": 1,
+    "empty": {},
+    "list": [],
+    "nested": [
+      [
+        1,
+        2
+      ],
+      [
+        {
+          "a": null
+        }
+      ]
+    ]
+  },
+  "keywords": [
+    "a",
+    {
+      "b": true
+    }
+  ],
+  "extra": {
+    "c": [
+      1.50
+    ]
+  },
+  "last": null
+}`, string(result))
+
+	serializer = NewJsonSerializationWriter(WithIndent("> ", "\t"))
+	require.NoError(t, serializer.WriteObjectValue("", parsable))
+	result, err = serializer.GetSerializedContent()
+	require.NoError(t, err)
+	var expected bytes.Buffer
+	require.NoError(t, json.Indent(&expected, []byte(source), "> ", "\t"))
+	assert.Equal(t, expected.String(), string(result))
+}
+
+func TestWriteIndentedCollectionsAndAdditionalData(t *testing.T) {
+	serializer := NewJsonSerializationWriter()
+	serializer.SetIndent("", "  ")
+	id := "1"
+	entity := internal.NewTestEntity()
+	entity.SetId(&id)
+	require.NoError(t, serializer.WriteCollectionOfObjectValues("entities", []absser.Parsable{entity, nil, internal.NewTestEntity()}))
+	require.NoError(t, serializer.WriteCollectionOfStringValues("names", []string{"a", "b"}))
+	require.NoError(t, serializer.WriteCollectionOfInt32Values("none", []int32{}))
+	require.NoError(t, serializer.WriteAdditionalData(map[string]interface{}{
+		"struct": TestStruct{Key: "value"},
+		"count":  int32(2),
+	}))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `"entities": [
+  {
+    "id": "1"
+  },
+  null,
+  {}
+],
+"names": [
+  "a",
+  "b"
+],
+"none": [],
+"count": 2,
+"struct": {
+  "key": "value"
+}`, string(result))
+
+	require.NoError(t, serializer.Reset())
+	require.NoError(t, serializer.WriteObjectValue("", entity))
+	result, err = serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"id\": \"1\"\n}", string(result))
+}
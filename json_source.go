@@ -0,0 +1,21 @@
+package jsonserialization
+
+import "sort"
+
+// jsonSource keeps what is needed to turn byte offsets of a payload into line and column numbers.
+type jsonSource struct {
+	// lineStarts holds the offset of the first byte of every line but the first one.
+	lineStarts []int64
+}
+
+// position returns the 1-based line and column of the byte at offset.
+func (s *jsonSource) position(offset int64) (int, int) {
+	line := sort.Search(len(s.lineStarts), func(i int) bool {
+		return s.lineStarts[i] > offset
+	})
+	lineStart := int64(0)
+	if line > 0 {
+		lineStart = s.lineStarts[line-1]
+	}
+	return line + 1, int(offset-lineStart) + 1
+}
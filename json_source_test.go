@@ -0,0 +1,38 @@
+package jsonserialization
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonSourcePosition(t *testing.T) {
+	// "ab\ncd\n\nef"
+	source := &jsonSource{lineStarts: []int64{3, 6, 7}}
+
+	line, column := source.position(0)
+	assert.Equal(t, []int{1, 1}, []int{line, column})
+	line, column = source.position(4)
+	assert.Equal(t, []int{2, 2}, []int{line, column})
+	line, column = source.position(7)
+	assert.Equal(t, []int{4, 1}, []int{line, column})
+}
+
+func TestOffsetsFollowSmallReads(t *testing.T) {
+	source := "{\n \"a\" :\t[ true ,\r\n null, \"x\" ] }"
+	parseNode, err := NewJsonParseNodeFromReader(iotest.OneByteReader(strings.NewReader(source)))
+	require.NoError(t, err)
+
+	a, err := parseNode.GetChildNode("a")
+	require.NoError(t, err)
+	node := a.(*JsonParseNode)
+	assert.Equal(t, []int64{
+		int64(strings.Index(source, "true")),
+		int64(strings.Index(source, "null")),
+		int64(strings.Index(source, `"x"`)),
+	}, node.elementOffsets)
+	assert.Equal(t, int64(strings.Index(source, "[")), node.offset)
+}
@@ -0,0 +1,73 @@
+package jsonserialization
+
+import (
+	"errors"
+	"io"
+)
+
+// defaultStreamBufferSize is the number of bytes writers bound to an output buffer by default.
+const defaultStreamBufferSize = 32 * 1024
+
+// WithStreamBufferSize sets the number of bytes writers bound to an output buffer before flushing them,
+// 32 KiB by default. Values that are larger than the size are still buffered whole.
+func WithStreamBufferSize(size int) JsonSerializationWriterOption {
+	return serializationWriterOptionFunc(func(options *serializationWriterOptions) {
+		options.streamBufferSize = size
+	})
+}
+
+// NewJsonSerializationWriterTo creates a JsonSerializationWriter writing the content to the output as it
+// goes, such as an io.Pipe feeding the body of a request, rather than keeping it all in memory. Content is
+// flushed once the root value completes, and when a member or an element starts or an object or an array
+// ends while the buffer holds the size set with WithStreamBufferSize. Every write returns the first error of the output,
+// and nothing is written once it failed. Call Flush, or Close, once the content is written to write what
+// is left. Canonical content is only written by Flush, since it depends on the whole content.
+func NewJsonSerializationWriterTo(output io.Writer, opts ...JsonSerializationWriterOption) (*JsonSerializationWriter, error) {
+	if output == nil {
+		return nil, errors.New("output is nil")
+	}
+	writer := NewJsonSerializationWriter(opts...)
+	writer.output = output
+	return writer, nil
+}
+
+// Flush writes the content left in the buffer of a writer bound to an output, and returns the first error
+// of the output.
+func (w *JsonSerializationWriter) Flush() error {
+	if w.output == nil {
+		return errors.New("the writer is not bound to an output")
+	}
+	w.flush(true)
+	return w.err
+}
+
+// flushIfDue flushes the buffer when the root value completed or the buffer holds the buffer size.
+func (w *JsonSerializationWriter) flushIfDue() {
+	options := w.getOptions()
+	if w.output == nil || options.canonical {
+		return
+	}
+	size := options.streamBufferSize
+	if size <= 0 {
+		size = defaultStreamBufferSize
+	}
+	if len(w.containers) == 1 || w.getWriter().Len() >= size {
+		w.flush(false)
+	}
+}
+
+// flush writes the content of the buffer to the output, canonical content only once it is final.
+func (w *JsonSerializationWriter) flush(final bool) {
+	if w.err != nil {
+		return
+	}
+	buffer := w.getWriter()
+	content := buffer.Bytes()
+	if final && w.getOptions().canonical && len(content) != 0 {
+		content, w.err = canonicalizeJson(content)
+	}
+	if w.err == nil && len(content) != 0 {
+		_, w.err = w.output.Write(content)
+	}
+	buffer.Reset()
+}
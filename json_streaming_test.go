@@ -0,0 +1,179 @@
+package jsonserialization
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingWriter records the chunks written to it, failing once it holds limit bytes when limit is set.
+type recordingWriter struct {
+	bytes.Buffer
+	chunks int
+	limit  int
+}
+
+func (r *recordingWriter) Write(p []byte) (int, error) {
+	if r.limit > 0 && r.Len()+len(p) > r.limit {
+		return 0, errors.New("the output is full")
+	}
+	r.chunks++
+	return r.Buffer.Write(p)
+}
+
+func testEntities(count int) []absser.Parsable {
+	entities := make([]absser.Parsable, count)
+	for i := range entities {
+		entity := internal.NewTestEntity()
+		id := fmt.Sprintf("%d", i)
+		entity.SetId(&id)
+		entities[i] = entity
+	}
+	return entities
+}
+
+func expectedContent(t *testing.T, write func(*JsonSerializationWriter) error, opts ...JsonSerializationWriterOption) string {
+	serializer := NewJsonSerializationWriter(opts...)
+	defer serializer.Close()
+	require.NoError(t, write(serializer))
+	content, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestStreamingWriterFlushesAsObjectsComplete(t *testing.T) {
+	entities := testEntities(1000)
+	write := func(serializer *JsonSerializationWriter) error {
+		if err := serializer.WriteCollectionOfObjectValues("", entities); err != nil {
+			return err
+		}
+		return serializer.WriteCollectionOfStringValues("", []string{})
+	}
+	output := &recordingWriter{}
+	serializer, err := NewJsonSerializationWriterTo(output, WithStreamBufferSize(256))
+	require.NoError(t, err)
+	buffered := 0
+	require.NoError(t, serializer.SetOnAfterObjectSerialization(func(absser.Parsable) error {
+		buffered = max(buffered, serializer.getWriter().Len())
+		return nil
+	}))
+	require.NoError(t, serializer.WriteCollectionOfObjectValues("", entities))
+	assert.Less(t, buffered, 256+32)
+	// the root array completed, it was flushed whole
+	assert.Greater(t, output.chunks, 10)
+	assert.Zero(t, serializer.getWriter().Len())
+	assert.True(t, bytes.HasSuffix(output.Bytes(), []byte(`{"id":"999"}]`)))
+	require.NoError(t, serializer.WriteCollectionOfStringValues("", []string{}))
+	require.NoError(t, serializer.Close())
+	assert.Equal(t, expectedContent(t, write), output.String())
+}
+
+func TestStreamingWriterTrimsSeparatorsAcrossFlushes(t *testing.T) {
+	write := func(serializer *JsonSerializationWriter) error {
+		if err := serializer.WriteObjectValue("entity", testEntities(1)[0]); err != nil {
+			return err
+		}
+		if err := serializer.WriteCollectionOfInt32Values("numbers", []int32{1, 2, 3}); err != nil {
+			return err
+		}
+		return serializer.WriteAdditionalData(map[string]interface{}{"nested": absser.NewUntypedObject(map[string]absser.UntypedNodeable{
+			"list": absser.NewUntypedArray([]absser.UntypedNodeable{absser.NewUntypedBoolean(true), absser.NewUntypedArray(nil)}),
+		})})
+	}
+	for _, opts := range [][]JsonSerializationWriterOption{nil, {WithIndent("", "  ")}} {
+		for _, size := range []int{1, 8, 1024} {
+			output := &recordingWriter{}
+			serializer, err := NewJsonSerializationWriterTo(output, append([]JsonSerializationWriterOption{WithStreamBufferSize(size)}, opts...)...)
+			require.NoError(t, err)
+			require.NoError(t, write(serializer))
+			content, err := serializer.GetSerializedContent()
+			require.NoError(t, err)
+			assert.Nil(t, content)
+			assert.Equal(t, expectedContent(t, write, opts...), output.String())
+		}
+	}
+}
+
+func TestStreamingWriterThroughPipe(t *testing.T) {
+	entities := testEntities(5000)
+	reader, writer := io.Pipe()
+	go func() {
+		serializer, err := NewJsonSerializationWriterTo(writer)
+		if err == nil {
+			err = serializer.WriteCollectionOfObjectValues("", entities)
+		}
+		if err == nil {
+			err = serializer.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	parseNode, err := NewJsonParseNode(content)
+	require.NoError(t, err)
+	values, err := parseNode.GetCollectionOfObjectValues(internal.CreateTestEntityFromDiscriminator)
+	require.NoError(t, err)
+	require.Len(t, values, 5000)
+	assert.Equal(t, "4999", *values[4999].(*internal.TestEntity).GetId())
+}
+
+func TestStreamingWriterSurfacesWriteErrors(t *testing.T) {
+	output := &recordingWriter{limit: 100}
+	serializer, err := NewJsonSerializationWriterTo(output, WithStreamBufferSize(16))
+	require.NoError(t, err)
+	err = serializer.WriteCollectionOfObjectValues("", testEntities(100))
+	assert.EqualError(t, err, "the output is full")
+
+	value := "value"
+	assert.EqualError(t, serializer.WriteStringValue("key", &value), "the output is full")
+	assert.EqualError(t, serializer.WriteStringValue("key", nil), "the output is full")
+	assert.EqualError(t, serializer.WriteNullValue("key"), "the output is full")
+	assert.EqualError(t, serializer.WriteAdditionalData(nil), "the output is full")
+	assert.EqualError(t, serializer.WriteObjectValue("key", testEntities(1)[0]), "the output is full")
+	assert.EqualError(t, serializer.Flush(), "the output is full")
+	assert.Zero(t, serializer.getWriter().Len())
+	assert.LessOrEqual(t, output.Len(), 100)
+	assert.EqualError(t, serializer.Close(), "the output is full")
+}
+
+func TestStreamingWriterSurfacesWriteErrorsOfNumbers(t *testing.T) {
+	output := &recordingWriter{limit: 1}
+	serializer, err := NewJsonSerializationWriterTo(output)
+	require.NoError(t, err)
+	value := "value"
+	require.NoError(t, serializer.WriteStringValue("", &value))
+	assert.EqualError(t, serializer.Flush(), "the output is full")
+
+	assert.EqualError(t, serializer.WriteObjectValue("", absser.NewUntypedNode(json.Number("1"))), "the output is full")
+	assert.EqualError(t, serializer.WriteAdditionalData(map[string]interface{}{"number": json.Number("1")}), "the output is full")
+	number := json.Number("2")
+	assert.EqualError(t, serializer.WriteAdditionalData(map[string]interface{}{"number": &number}), "the output is full")
+}
+
+func TestStreamingWriterWritesCanonicalContentOnFlush(t *testing.T) {
+	output := &recordingWriter{}
+	serializer, err := NewJsonSerializationWriterTo(output, WithCanonicalJson(), WithStreamBufferSize(1))
+	require.NoError(t, err)
+	require.NoError(t, serializer.WriteObjectValue("", absser.NewUntypedObject(map[string]absser.UntypedNodeable{
+		"b": absser.NewUntypedDouble(1.0),
+		"a": absser.NewUntypedArray([]absser.UntypedNodeable{absser.NewUntypedString("x")}),
+	})))
+	assert.Zero(t, output.Len())
+	require.NoError(t, serializer.Flush())
+	assert.Equal(t, `{"a":["x"],"b":1}`, output.String())
+}
+
+func TestStreamingWriterRequiresOutput(t *testing.T) {
+	_, err := NewJsonSerializationWriterTo(nil)
+	assert.Error(t, err)
+	assert.Error(t, NewJsonSerializationWriter().Flush())
+}
@@ -0,0 +1,235 @@
+package jsonserialization
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts accepted by WithTimeLayouts besides the layouts of the time package.
+const (
+	// TimeLayoutZoneless reads RFC 3339 date-times without an offset, in the location set by WithTimeLocation.
+	TimeLayoutZoneless = "2006-01-02T15:04:05.999999999"
+	// TimeLayoutSpaceSeparated reads RFC 3339 date-times with a space between the date and the time.
+	TimeLayoutSpaceSeparated = "2006-01-02 15:04:05.999999999Z07:00"
+	// TimeLayoutSpaceSeparatedZoneless reads date-times with a space between the date and the time and
+	// without an offset, in the location set by WithTimeLocation.
+	TimeLayoutSpaceSeparatedZoneless = "2006-01-02 15:04:05.999999999"
+	// TimeLayoutMicrosoftDate reads the legacy "/Date(1690000000000)/" form, which holds milliseconds since
+	// the Unix epoch and an optional "+hhmm" offset.
+	TimeLayoutMicrosoftDate = "/Date(ms)/"
+	// TimeLayoutUnixSeconds reads JSON numbers as seconds since the Unix epoch.
+	TimeLayoutUnixSeconds = "unix-seconds"
+	// TimeLayoutUnixMilliseconds reads JSON numbers as milliseconds since the Unix epoch.
+	TimeLayoutUnixMilliseconds = "unix-milliseconds"
+)
+
+// defaultTimeLayouts are the layouts GetTimeValue accepts unless WithTimeLayouts is used.
+var defaultTimeLayouts = []string{time.RFC3339Nano, TimeLayoutZoneless}
+
+// WithTimeLayouts sets the layouts GetTimeValue accepts, tried in order. Layouts are the ones of the
+// time package or the TimeLayout constants of this package. The default accepts RFC 3339 date-times,
+// fractional seconds included, and TimeLayoutZoneless.
+func WithTimeLayouts(layouts ...string) JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.timeLayouts = layouts
+	})
+}
+
+// WithTimeLocation sets the location of date-times that have no offset, time.Local by default.
+// Values read from epoch numbers and "/Date(ms)/" strings without an offset are returned in UTC.
+func WithTimeLocation(location *time.Location) JsonParseNodeOption {
+	return parseNodeOptionFunc(func(options *parseNodeOptions) {
+		options.timeLocation = location
+	})
+}
+
+// parseTime reads a date-time from a raw value of the tree according to the options.
+func parseTime(rawValue interface{}, options *parseNodeOptions) (*time.Time, error) {
+	layouts := options.timeLayouts
+	if layouts == nil {
+		layouts = defaultTimeLayouts
+	}
+	location := options.timeLocation
+	if location == nil {
+		location = time.Local
+	}
+	text, isString := rawValue.(*string)
+	number, isNumber := timeNumber(rawValue)
+	var firstErr error
+	for _, layout := range layouts {
+		var parsed time.Time
+		var err error
+		switch layout {
+		case TimeLayoutUnixSeconds, TimeLayoutUnixMilliseconds:
+			if !isNumber {
+				continue
+			}
+			parsed, err = parseEpoch(number, layout == TimeLayoutUnixMilliseconds)
+		case TimeLayoutMicrosoftDate:
+			if !isString {
+				continue
+			}
+			parsed, err = parseMicrosoftDate(*text)
+		default:
+			if !isString {
+				continue
+			}
+			parsed, err = time.ParseInLocation(layout, *text, location)
+		}
+		if err == nil {
+			return &parsed, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if isString {
+		return nil, fmt.Errorf("value %q does not match any of the time layouts", *text)
+	}
+	if isNumber {
+		return nil, fmt.Errorf("value '%s' is not read as a time, no Unix epoch layout is set", number)
+	}
+	return nil, fmt.Errorf("value '%v' is not compatible with type time.Time", rawValue)
+}
+
+// timeNumber returns the text of a raw number value.
+func timeNumber(rawValue interface{}) (string, bool) {
+	switch value := rawValue.(type) {
+	case *json.Number:
+		return value.String(), true
+	case *float64:
+		return strconv.FormatFloat(*value, 'f', -1, 64), true
+	case *float32:
+		return strconv.FormatFloat(float64(*value), 'f', -1, 32), true
+	case *int64:
+		return strconv.FormatInt(*value, 10), true
+	case *int32:
+		return strconv.FormatInt(int64(*value), 10), true
+	default:
+		return "", false
+	}
+}
+
+// parseEpoch reads a number of seconds or milliseconds since the Unix epoch, fractions included.
+func parseEpoch(number string, milliseconds bool) (time.Time, error) {
+	value, ok := new(big.Float).SetPrec(128).SetString(number)
+	if !ok {
+		return time.Time{}, fmt.Errorf("value '%s' is not a valid number", number)
+	}
+	unit := big.NewFloat(float64(time.Second))
+	if milliseconds {
+		unit = big.NewFloat(float64(time.Millisecond))
+	}
+	product := new(big.Float).SetPrec(128).Mul(value, unit)
+	// beyond 128 bits the seconds cannot fit an int64, and 1e100000000 would build a huge integer
+	if product.IsInf() || product.MantExp(nil) > 128 {
+		return time.Time{}, fmt.Errorf("value '%s' is out of the range of time.Time", number)
+	}
+	nanoseconds, _ := product.Int(nil)
+	seconds, remainder := nanoseconds.QuoRem(nanoseconds, big.NewInt(int64(time.Second)), new(big.Int))
+	if !seconds.IsInt64() {
+		return time.Time{}, fmt.Errorf("value '%s' is out of the range of time.Time", number)
+	}
+	return time.Unix(seconds.Int64(), remainder.Int64()).UTC(), nil
+}
+
+// parseMicrosoftDate reads the legacy "/Date(ms)/" and "/Date(ms+hhmm)/" forms.
+func parseMicrosoftDate(text string) (time.Time, error) {
+	content, ok := strings.CutPrefix(text, "/Date(")
+	if ok {
+		content, ok = strings.CutSuffix(content, ")/")
+	}
+	if !ok || content == "" {
+		return time.Time{}, fmt.Errorf("value %q is not a /Date(ms)/ date", text)
+	}
+	milliseconds, offset := content, ""
+	if i := strings.LastIndexAny(content, "+-"); i > 0 {
+		milliseconds, offset = content[:i], content[i:]
+	}
+	value, err := strconv.ParseInt(milliseconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("value %q is not a /Date(ms)/ date: %w", text, err)
+	}
+	parsed := time.UnixMilli(value).UTC()
+	if offset == "" {
+		return parsed, nil
+	}
+	zone, err := time.Parse("-0700", offset)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("value %q has an invalid offset", text)
+	}
+	return parsed.In(zone.Location()), nil
+}
+
+// TimeFormat is the format WriteTimeValue writes date-times in.
+type TimeFormat int
+
+const (
+	// TimeFormatRFC3339 writes RFC 3339 strings with a precision of a second, the default.
+	TimeFormatRFC3339 TimeFormat = iota
+	// TimeFormatRFC3339Nano writes RFC 3339 strings with as many fractional digits as needed.
+	TimeFormatRFC3339Nano
+	// TimeFormatRFC3339Milliseconds writes RFC 3339 strings with exactly three fractional digits.
+	TimeFormatRFC3339Milliseconds
+	// TimeFormatUnixSeconds writes numbers of seconds since the Unix epoch, with a fraction when needed.
+	TimeFormatUnixSeconds
+	// TimeFormatUnixMilliseconds writes whole numbers of milliseconds since the Unix epoch.
+	TimeFormatUnixMilliseconds
+)
+
+// rfc3339Milliseconds is the layout of TimeFormatRFC3339Milliseconds.
+const rfc3339Milliseconds = "2006-01-02T15:04:05.000Z07:00"
+
+// WithTimeFormat sets the format WriteTimeValue, WriteCollectionOfTimeValues and WriteAdditionalData
+// write date-times in.
+func WithTimeFormat(format TimeFormat) JsonSerializationWriterOption {
+	return serializationWriterOptionFunc(func(options *serializationWriterOptions) {
+		options.timeFormat = format
+	})
+}
+
+// WithUTCTimes converts date-times to UTC before they are written.
+func WithUTCTimes() JsonSerializationWriterOption {
+	return serializationWriterOptionFunc(func(options *serializationWriterOptions) {
+		options.utcTimes = true
+	})
+}
+
+// formatTime returns the text of a date-time according to the options, isNumber being set when the text
+// is a JSON number rather than the content of a string.
+func formatTime(value time.Time, options *serializationWriterOptions) (text string, isNumber bool) {
+	if options.utcTimes {
+		value = value.UTC()
+	}
+	switch options.timeFormat {
+	case TimeFormatRFC3339Nano:
+		return value.Format(time.RFC3339Nano), false
+	case TimeFormatRFC3339Milliseconds:
+		return value.Format(rfc3339Milliseconds), false
+	case TimeFormatUnixSeconds:
+		seconds := strconv.FormatInt(value.Unix(), 10)
+		if nanoseconds := value.Nanosecond(); nanoseconds != 0 {
+			if value.Unix() < 0 {
+				// the fraction is counted backwards from the next second
+				seconds = strconv.FormatInt(value.Unix()+1, 10)
+				if value.Unix()+1 == 0 {
+					seconds = "-0"
+				}
+				nanoseconds = int(time.Second) - nanoseconds
+			}
+			seconds += strings.TrimRight(fmt.Sprintf(".%09d", nanoseconds), "0")
+		}
+		return seconds, true
+	case TimeFormatUnixMilliseconds:
+		return strconv.FormatInt(value.UnixMilli(), 10), true
+	default:
+		return value.Format(time.RFC3339), false
+	}
+}
@@ -0,0 +1,551 @@
+package jsonserialization
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// tokenKind is the kind of a token read from a payload.
+type tokenKind uint8
+
+const (
+	tokenBeginObject tokenKind = iota
+	tokenEndObject
+	tokenBeginArray
+	tokenEndArray
+	tokenString
+	tokenNumber
+	tokenTrue
+	tokenFalse
+	tokenNull
+)
+
+// jsonToken is a token of a payload. Tokens are returned by value so reading them does not allocate.
+type jsonToken struct {
+	kind tokenKind
+	// offset is the offset of the first byte of the token, -1 when it is unknown.
+	offset int64
+	// text holds the content of strings, without the quotes, and the lexeme of numbers. It is only
+	// valid until the next token is read.
+	text []byte
+	// escaped is set when the content of a string must be decoded before use.
+	escaped bool
+}
+
+// stringValue returns the value of a string token.
+func (t *jsonToken) stringValue() string {
+	if t.escaped {
+		return unescapeString(t.text)
+	}
+	return string(t.text)
+}
+
+// tokenReader reads the tokens of a payload for a treeLoader. Separators are checked by the reader
+// and never returned.
+type tokenReader interface {
+	// readToken returns the next token, io.EOF once the content is exhausted between two values.
+	readToken() (jsonToken, error)
+	// inputOffset returns the offset of the next byte to read, -1 when offsets are not tracked.
+	inputOffset() int64
+	// source returns the line breaks met so far, nil when offsets are not tracked.
+	source() *jsonSource
+}
+
+// syntaxError reports malformed JSON at an offset of the payload.
+type syntaxError struct {
+	msg    string
+	offset int64
+	err    error
+}
+
+func (e *syntaxError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns io.ErrUnexpectedEOF for truncated payloads.
+func (e *syntaxError) Unwrap() error {
+	return e.err
+}
+
+// scanState is what a jsonScanner expects next.
+type scanState uint8
+
+const (
+	// scanBetweenValues is the state before and after top-level values.
+	scanBetweenValues scanState = iota
+	scanValue
+	scanValueOrEnd
+	scanKey
+	scanKeyOrEnd
+	scanColon
+	scanCommaOrEnd
+)
+
+// scanReadSize is the minimum number of bytes asked from a reader at once.
+const scanReadSize = 4096
+
+// jsonScanner is a single-pass tokenizer that validates the payload while it reads it. Strings and
+// numbers are returned as slices of its buffer and only copied by the caller that keeps them.
+type jsonScanner struct {
+	buf []byte
+	// pos is the index in buf of the next byte to read.
+	pos int
+	// start is the index in buf of the token being read, the bytes before it can be discarded.
+	start int
+	// base is the offset in the payload of buf[0].
+	base   int64
+	reader io.Reader
+	// readErr is the error returned by the reader, io.EOF once it is exhausted.
+	readErr error
+	// stack holds the opening delimiters of the open containers, backed by stackBuf for shallow payloads.
+	stack    []byte
+	stackBuf [32]byte
+	state    scanState
+	lines    jsonSource
+	// shared holds the line breaks of the payload when they were recorded by another scanner, see newSubtreeScanner.
+	shared *jsonSource
+}
+
+// newBytesScanner creates a scanner over content that is already in memory. The content is never modified.
+func newBytesScanner(content []byte) *jsonScanner {
+	s := &jsonScanner{buf: content, readErr: io.EOF}
+	s.stack = s.stackBuf[:0]
+	return s
+}
+
+// newSubtreeScanner creates a scanner over the value located between the start and end offsets of content,
+// whose line breaks were already recorded in source when the value was skipped.
+func newSubtreeScanner(content []byte, start, end int64, source *jsonSource) *jsonScanner {
+	s := newBytesScanner(content[:end])
+	s.pos = int(start)
+	s.start = s.pos
+	s.shared = source
+	return s
+}
+
+// newReaderScanner creates a scanner that reads its content from reader as tokens are requested.
+func newReaderScanner(reader io.Reader) *jsonScanner {
+	s := &jsonScanner{reader: reader}
+	s.stack = s.stackBuf[:0]
+	return s
+}
+
+func (s *jsonScanner) inputOffset() int64 {
+	return s.base + int64(s.pos)
+}
+
+func (s *jsonScanner) source() *jsonSource {
+	if s.shared != nil {
+		return s.shared
+	}
+	return &s.lines
+}
+
+// fill reads more content, discarding the bytes before the token being read. It returns false when
+// nothing more can be read.
+func (s *jsonScanner) fill() bool {
+	if s.readErr != nil {
+		return false
+	}
+	if s.start > 0 {
+		n := copy(s.buf, s.buf[s.start:])
+		s.buf = s.buf[:n]
+		s.base += int64(s.start)
+		s.pos -= s.start
+		s.start = 0
+	}
+	if cap(s.buf)-len(s.buf) < scanReadSize {
+		grown := make([]byte, len(s.buf), 2*cap(s.buf)+scanReadSize)
+		copy(grown, s.buf)
+		s.buf = grown
+	}
+	for {
+		n, err := s.reader.Read(s.buf[len(s.buf):cap(s.buf)])
+		s.buf = s.buf[:len(s.buf)+n]
+		if err != nil {
+			s.readErr = err
+		}
+		if n > 0 {
+			return true
+		}
+		if err != nil {
+			return false
+		}
+	}
+}
+
+// peek returns the next byte without consuming it, false at the end of the content.
+func (s *jsonScanner) peek() (byte, bool) {
+	if s.pos < len(s.buf) || s.fill() {
+		return s.buf[s.pos], true
+	}
+	return 0, false
+}
+
+// endError returns the error reporting the content ended too early.
+func (s *jsonScanner) endError() error {
+	if s.readErr != nil && s.readErr != io.EOF {
+		return s.readErr
+	}
+	return &syntaxError{msg: "unexpected end of JSON input", offset: s.inputOffset(), err: io.ErrUnexpectedEOF}
+}
+
+// charError returns the error reporting an unexpected byte at pos.
+func (s *jsonScanner) charError(c byte, context string) error {
+	return &syntaxError{msg: fmt.Sprintf("invalid character %s %s", quoteChar(c), context), offset: s.inputOffset()}
+}
+
+// skipWhitespace moves to the next significant byte, recording line breaks on the way.
+func (s *jsonScanner) skipWhitespace() (byte, bool) {
+	for {
+		for s.pos < len(s.buf) {
+			c := s.buf[s.pos]
+			switch c {
+			case ' ', '\t', '\r':
+			case '\n':
+				if s.shared == nil {
+					s.lines.lineStarts = append(s.lines.lineStarts, s.inputOffset()+1)
+				}
+			default:
+				return c, true
+			}
+			s.pos++
+		}
+		s.start = s.pos
+		if !s.fill() {
+			return 0, false
+		}
+	}
+}
+
+func (s *jsonScanner) readToken() (jsonToken, error) {
+	for {
+		c, ok := s.skipWhitespace()
+		if !ok {
+			if s.state == scanBetweenValues && (s.readErr == io.EOF || s.readErr == nil) {
+				return jsonToken{}, io.EOF
+			}
+			return jsonToken{}, s.endError()
+		}
+		s.start = s.pos
+		switch s.state {
+		case scanColon:
+			if c != ':' {
+				return jsonToken{}, s.charError(c, "after object key")
+			}
+			s.pos++
+			s.state = scanValue
+			continue
+		case scanCommaOrEnd:
+			container := s.stack[len(s.stack)-1]
+			if c == ',' {
+				s.pos++
+				if container == '{' {
+					s.state = scanKey
+				} else {
+					s.state = scanValue
+				}
+				continue
+			}
+			if container == '{' && c == '}' || container == '[' && c == ']' {
+				return s.endContainer(), nil
+			}
+			if container == '{' {
+				return jsonToken{}, s.charError(c, "after object key:value pair")
+			}
+			return jsonToken{}, s.charError(c, "after array element")
+		case scanKeyOrEnd, scanKey:
+			if c == '}' && s.state == scanKeyOrEnd {
+				return s.endContainer(), nil
+			}
+			if c != '"' {
+				return jsonToken{}, s.charError(c, "looking for beginning of object key string")
+			}
+			token, err := s.scanString()
+			s.state = scanColon
+			return token, err
+		case scanValueOrEnd:
+			if c == ']' {
+				return s.endContainer(), nil
+			}
+		}
+		return s.scanValue(c)
+	}
+}
+
+// afterValue moves to the state following a complete value.
+func (s *jsonScanner) afterValue() {
+	if len(s.stack) == 0 {
+		s.state = scanBetweenValues
+	} else {
+		s.state = scanCommaOrEnd
+	}
+}
+
+func (s *jsonScanner) endContainer() jsonToken {
+	token := jsonToken{kind: tokenEndObject, offset: s.inputOffset()}
+	if s.stack[len(s.stack)-1] == '[' {
+		token.kind = tokenEndArray
+	}
+	s.stack = s.stack[:len(s.stack)-1]
+	s.pos++
+	s.afterValue()
+	return token
+}
+
+func (s *jsonScanner) scanValue(c byte) (jsonToken, error) {
+	offset := s.inputOffset()
+	switch c {
+	case '{', '[':
+		s.stack = append(s.stack, c)
+		s.pos++
+		if c == '{' {
+			s.state = scanKeyOrEnd
+			return jsonToken{kind: tokenBeginObject, offset: offset}, nil
+		}
+		s.state = scanValueOrEnd
+		return jsonToken{kind: tokenBeginArray, offset: offset}, nil
+	case '"':
+		token, err := s.scanString()
+		s.afterValue()
+		return token, err
+	case 't':
+		return s.scanLiteral("true", tokenTrue)
+	case 'f':
+		return s.scanLiteral("false", tokenFalse)
+	case 'n':
+		return s.scanLiteral("null", tokenNull)
+	}
+	if c == '-' || c >= '0' && c <= '9' {
+		token, err := s.scanNumber()
+		s.afterValue()
+		return token, err
+	}
+	return jsonToken{}, s.charError(c, "looking for beginning of value")
+}
+
+func (s *jsonScanner) scanLiteral(literal string, kind tokenKind) (jsonToken, error) {
+	offset := s.inputOffset()
+	for i := 0; i < len(literal); i++ {
+		c, ok := s.peek()
+		if !ok {
+			return jsonToken{}, s.endError()
+		}
+		if c != literal[i] {
+			return jsonToken{}, s.charError(c, "in literal "+literal)
+		}
+		s.pos++
+	}
+	s.afterValue()
+	return jsonToken{kind: kind, offset: offset}, nil
+}
+
+// scanDigits consumes a run of digits and returns how many there were.
+func (s *jsonScanner) scanDigits() int {
+	count := 0
+	for {
+		c, ok := s.peek()
+		if !ok || c < '0' || c > '9' {
+			return count
+		}
+		s.pos++
+		count++
+	}
+}
+
+func (s *jsonScanner) scanNumber() (jsonToken, error) {
+	offset := s.inputOffset()
+	if c, _ := s.peek(); c == '-' {
+		s.pos++
+	}
+	c, ok := s.peek()
+	switch {
+	case !ok:
+		return jsonToken{}, s.endError()
+	case c == '0':
+		s.pos++
+	case c >= '1' && c <= '9':
+		s.scanDigits()
+	default:
+		return jsonToken{}, s.charError(c, "in numeric literal")
+	}
+	if c, ok := s.peek(); ok && c == '.' {
+		s.pos++
+		if s.scanDigits() == 0 {
+			return jsonToken{}, s.digitError()
+		}
+	}
+	if c, ok := s.peek(); ok && (c == 'e' || c == 'E') {
+		s.pos++
+		if c, ok := s.peek(); ok && (c == '+' || c == '-') {
+			s.pos++
+		}
+		if s.scanDigits() == 0 {
+			return jsonToken{}, s.digitError()
+		}
+	}
+	return jsonToken{kind: tokenNumber, offset: offset, text: s.buf[s.start:s.pos]}, nil
+}
+
+// digitError reports a number missing digits after its fraction point or exponent.
+func (s *jsonScanner) digitError() error {
+	c, ok := s.peek()
+	if !ok {
+		return s.endError()
+	}
+	return s.charError(c, "in numeric literal")
+}
+
+func (s *jsonScanner) scanString() (jsonToken, error) {
+	offset := s.inputOffset()
+	s.pos++ // opening quote
+	escaped := false
+	nonASCII := false
+	for {
+		for s.pos < len(s.buf) {
+			c := s.buf[s.pos]
+			switch {
+			case c == '"':
+				text := s.buf[s.start+1 : s.pos]
+				s.pos++
+				if nonASCII && !escaped && !utf8.Valid(text) {
+					escaped = true
+				}
+				return jsonToken{kind: tokenString, offset: offset, text: text, escaped: escaped}, nil
+			case c == '\\':
+				escaped = true
+				if err := s.scanEscape(); err != nil {
+					return jsonToken{}, err
+				}
+				continue
+			case c < 0x20:
+				return jsonToken{}, s.charError(c, "in string literal")
+			case c >= utf8.RuneSelf:
+				nonASCII = true
+			}
+			s.pos++
+		}
+		if !s.fill() {
+			return jsonToken{}, s.endError()
+		}
+	}
+}
+
+// scanEscape consumes an escape sequence of a string, pos being on the backslash.
+func (s *jsonScanner) scanEscape() error {
+	s.pos++
+	c, ok := s.peek()
+	if !ok {
+		return s.endError()
+	}
+	switch c {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		s.pos++
+		return nil
+	case 'u':
+		s.pos++
+		for i := 0; i < 4; i++ {
+			c, ok := s.peek()
+			if !ok {
+				return s.endError()
+			}
+			if hexValue(c) < 0 {
+				return s.charError(c, "in \\u hexadecimal character escape")
+			}
+			s.pos++
+		}
+		return nil
+	default:
+		return s.charError(c, "in string escape code")
+	}
+}
+
+// hexValue returns the value of a hexadecimal digit, -1 for other bytes.
+func hexValue(c byte) rune {
+	switch {
+	case c >= '0' && c <= '9':
+		return rune(c - '0')
+	case c >= 'a' && c <= 'f':
+		return rune(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return rune(c-'A') + 10
+	}
+	return -1
+}
+
+// unescapeString decodes the content of a string the scanner validated. Invalid UTF-8 is replaced
+// by utf8.RuneError as encoding/json does.
+func unescapeString(text []byte) string {
+	result := make([]byte, 0, len(text))
+	for i := 0; i < len(text); {
+		c := text[i]
+		if c == '\\' {
+			i++
+			switch text[i] {
+			case 'b':
+				result = append(result, '\b')
+			case 'f':
+				result = append(result, '\f')
+			case 'n':
+				result = append(result, '\n')
+			case 'r':
+				result = append(result, '\r')
+			case 't':
+				result = append(result, '\t')
+			case 'u':
+				r := decodeHex(text[i+1 : i+5])
+				i += 5
+				if utf16.IsSurrogate(r) {
+					r2 := utf8.RuneError
+					if i+6 <= len(text) && text[i] == '\\' && text[i+1] == 'u' {
+						r2 = decodeHex(text[i+2 : i+6])
+					}
+					if decoded := utf16.DecodeRune(r, r2); decoded != utf8.RuneError {
+						r = decoded
+						i += 6
+					} else {
+						r = utf8.RuneError
+					}
+				}
+				result = utf8.AppendRune(result, r)
+				continue
+			default:
+				result = append(result, text[i])
+			}
+			i++
+			continue
+		}
+		if c < utf8.RuneSelf {
+			result = append(result, c)
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(text[i:])
+		result = utf8.AppendRune(result, r)
+		i += size
+	}
+	return string(result)
+}
+
+// decodeHex decodes the four hexadecimal digits of a \u escape.
+func decodeHex(text []byte) rune {
+	var r rune
+	for _, c := range text {
+		r = r<<4 | hexValue(c)
+	}
+	return r
+}
+
+// quoteChar formats a byte for error messages the way encoding/json does.
+func quoteChar(c byte) string {
+	if c == '\'' {
+		return `'\''`
+	}
+	if c == '"' {
+		return `'"'`
+	}
+	s := fmt.Sprintf("%q", string(rune(c)))
+	return "'" + s[1:len(s)-1] + "'"
+}
@@ -0,0 +1,106 @@
+package jsonserialization
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScannerUnescapesStrings(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Expected string
+	}{
+		{`"plain"`, "plain"},
+		{`"a\"b\\c\/d"`, `a"b\c/d`},
+		{`"\b\f\n\r\t"`, "\b\f\n\r\t"},
+		{`"été"`, "été"},
+		{`"😀"`, "😀"},
+		{`"\ud83d"`, "�"},
+		{`"\ude00x"`, "�x"},
+		{"\"caf\xc3\xa9\"", "café"},
+		{"\"bad\xff\"", "bad�"},
+	}
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			token, err := newBytesScanner([]byte(test.Input)).readToken()
+			require.NoError(t, err)
+			assert.Equal(t, tokenString, token.kind)
+			assert.Equal(t, test.Expected, token.stringValue())
+
+			// the result matches the standard library
+			var expected string
+			require.NoError(t, json.Unmarshal([]byte(test.Input), &expected))
+			assert.Equal(t, expected, token.stringValue())
+		})
+	}
+}
+
+func TestScannerRejectsInvalidJson(t *testing.T) {
+	inputs := []string{
+		`{"a":1,}`,
+		`[1,]`,
+		`[1 2]`,
+		`{"a" 1}`,
+		`{1:1}`,
+		`01`,
+		`1.`,
+		`1e`,
+		`-`,
+		`+1`,
+		`tru`,
+		`nul`,
+		`"\x"`,
+		`"\u12"`,
+		"\"a\nb\"",
+		`]`,
+		`{"a":1]`,
+		`[1}`,
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			assert.False(t, json.Valid([]byte(input)))
+			_, err := NewJsonParseNode([]byte(input))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestScannerReportsTruncatedContent(t *testing.T) {
+	for _, input := range []string{`{"a":`, `[1,2`, `"abc`, `{"a":tr`} {
+		_, err := NewJsonParseNode([]byte(input))
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), input)
+	}
+}
+
+func TestScannerReadsTokensAcrossSmallReads(t *testing.T) {
+	content := `{"name":"café","values":[1,-2.5e3,true,false,null],"nested":{"empty":{},"list":[]}}`
+	scanner := newReaderScanner(iotest.OneByteReader(strings.NewReader(content)))
+	var kinds []tokenKind
+	var texts []string
+	for {
+		token, err := scanner.readToken()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		kinds = append(kinds, token.kind)
+		if token.kind == tokenString || token.kind == tokenNumber {
+			texts = append(texts, token.stringValue())
+		}
+	}
+	assert.Equal(t, []tokenKind{
+		tokenBeginObject,
+		tokenString, tokenString,
+		tokenString, tokenBeginArray, tokenNumber, tokenNumber, tokenTrue, tokenFalse, tokenNull, tokenEndArray,
+		tokenString, tokenBeginObject, tokenString, tokenBeginObject, tokenEndObject, tokenString, tokenBeginArray, tokenEndArray, tokenEndObject,
+		tokenEndObject,
+	}, kinds)
+	assert.Equal(t, []string{"name", "café", "values", "1", "-2.5e3", "nested", "empty", "list"}, texts)
+}
@@ -0,0 +1,190 @@
+package jsonserialization
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// AmbiguousMatchError is returned by GetBestMatchingObjectValue when several candidates match an object
+// equally well.
+type AmbiguousMatchError struct {
+	// Pointer is the JSON Pointer of the object.
+	Pointer string
+	// Candidates are the indices of the candidates that tie.
+	Candidates []int
+	// Types are the types the candidates that tie create.
+	Types []string
+}
+
+// Error returns the description of the ambiguity.
+func (e *AmbiguousMatchError) Error() string {
+	return fmt.Sprintf("the object at %q matches the candidates %s equally well", e.Pointer, strings.Join(e.Types, ", "))
+}
+
+// matchScore tallies how the members of an object fit the fields of a candidate.
+type matchScore struct {
+	// mismatched counts the members whose deserializer failed, their value having another type.
+	mismatched int
+	// coerced counts the members read as numbers from strings.
+	coerced int
+	// matched counts the members read by a deserializer.
+	matched int
+	// unmatched counts the members the candidate has no deserializer for.
+	unmatched int
+}
+
+// compare returns a positive number when the score is better than the other, a negative one when it is worse.
+func (s matchScore) compare(other matchScore) int {
+	if s.mismatched != other.mismatched {
+		return other.mismatched - s.mismatched
+	}
+	if s.coerced != other.coerced {
+		return other.coerced - s.coerced
+	}
+	if s.matched != other.matched {
+		return s.matched - other.matched
+	}
+	return other.unmatched - s.unmatched
+}
+
+// GetBestMatchingObjectValue reads the object with the candidate whose type fits its members best, for union
+// types whose members have no discriminator. Each candidate reads the members it has a deserializer for;
+// the members it fails to read count against it first, then the strings it reads as numbers, then the
+// members it reads count for it and the members it has no deserializer for against it. It returns an
+// *AmbiguousMatchError when the best candidates tie. Hooks are only invoked when the best candidate reads
+// the object.
+func (n *JsonParseNode) GetBestMatchingObjectValue(candidates ...absser.ParsableFactory) (absser.Parsable, error) {
+	if isNil(n) || isNil(n.value) {
+		return nil, nil
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("candidates are empty")
+	}
+	properties, ok := n.value.(map[string]interface{})
+	if !ok {
+		return nil, n.newParseError("object", fmt.Errorf("type '%T' is not compatible with type object", n.value))
+	}
+	// candidates read a copy of the node, so the reads that score them invoke no hook nor collect errors
+	scratch := *n
+	scratch.onBeforeAssignFieldValues = nil
+	scratch.onAfterAssignFieldValues = nil
+	scratch.collector = nil
+
+	best := []int(nil)
+	var bestScore matchScore
+	var bestTypes []string
+	var firstErr error
+	for i, candidate := range candidates {
+		if candidate == nil {
+			return nil, fmt.Errorf("candidate %d is nil", i)
+		}
+		value, err := candidate(&scratch)
+		if err != nil || isNil(value) {
+			if firstErr == nil && err != nil {
+				firstErr = err
+			}
+			continue
+		}
+		score, err := scratch.scoreMatch(value, properties)
+		if err != nil {
+			return nil, err
+		}
+		comparison := 1
+		if best != nil {
+			comparison = score.compare(bestScore)
+		}
+		if comparison > 0 {
+			best, bestScore, bestTypes = []int{i}, score, []string{fmt.Sprintf("%T", value)}
+		} else if comparison == 0 {
+			best, bestTypes = append(best, i), append(bestTypes, fmt.Sprintf("%T", value))
+		}
+	}
+	if best == nil {
+		if firstErr == nil {
+			firstErr = errors.New("no candidate created a value")
+		}
+		return nil, n.newParseError("", firstErr)
+	}
+	if len(best) > 1 {
+		return nil, &AmbiguousMatchError{Pointer: n.pointer(), Candidates: best, Types: bestTypes}
+	}
+	return n.GetObjectValue(candidates[best[0]])
+}
+
+// scoreMatch reads the members of the object into the value of a candidate and tallies how they fit.
+func (n *JsonParseNode) scoreMatch(value absser.Parsable, properties map[string]interface{}) (matchScore, error) {
+	var score matchScore
+	fields := n.namedFieldDeserializers(value.GetFieldDeserializers())
+	for key, rawValue := range properties {
+		field := fields[key]
+		if field == nil {
+			score.unmatched++
+			continue
+		}
+		childNode, err := n.memberNode(key, rawValue)
+		if err != nil {
+			return score, err
+		}
+		if childNode == nil {
+			err = field(childNode)
+		} else {
+			probe := &matchProbe{JsonParseNode: childNode}
+			err = field(probe)
+			if probe.coerced {
+				score.coerced++
+			}
+		}
+		if err != nil {
+			score.mismatched++
+		} else {
+			score.matched++
+		}
+	}
+	return score, nil
+}
+
+// matchProbe is the node candidates read members through, recording whether they read a string as a number.
+type matchProbe struct {
+	*JsonParseNode
+	coerced bool
+}
+
+// readNumber records that a number is read from a string.
+func (p *matchProbe) readNumber() {
+	if _, ok := p.value.(*string); ok {
+		p.coerced = true
+	}
+}
+
+func (p *matchProbe) GetInt8Value() (*int8, error) {
+	p.readNumber()
+	return p.JsonParseNode.GetInt8Value()
+}
+
+func (p *matchProbe) GetByteValue() (*byte, error) {
+	p.readNumber()
+	return p.JsonParseNode.GetByteValue()
+}
+
+func (p *matchProbe) GetInt32Value() (*int32, error) {
+	p.readNumber()
+	return p.JsonParseNode.GetInt32Value()
+}
+
+func (p *matchProbe) GetInt64Value() (*int64, error) {
+	p.readNumber()
+	return p.JsonParseNode.GetInt64Value()
+}
+
+func (p *matchProbe) GetFloat32Value() (*float32, error) {
+	p.readNumber()
+	return p.JsonParseNode.GetFloat32Value()
+}
+
+func (p *matchProbe) GetFloat64Value() (*float64, error) {
+	p.readNumber()
+	return p.JsonParseNode.GetFloat64Value()
+}
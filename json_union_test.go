@@ -0,0 +1,73 @@
+package jsonserialization
+
+import (
+	"errors"
+	"testing"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBestMatchingObjectValue(t *testing.T) {
+	candidates := []absser.ParsableFactory{internal.CreateTestEntityFromDiscriminator, internal.CreateSecondTestEntityFromDiscriminator}
+
+	parseNode, err := NewJsonParseNode([]byte(`{"id": 5, "displayName": "Parse Node"}`))
+	require.NoError(t, err)
+	value, err := parseNode.GetBestMatchingObjectValue(candidates...)
+	require.NoError(t, err)
+	second, ok := value.(*internal.SecondTestEntity)
+	require.True(t, ok, "%T", value)
+	assert.Equal(t, int64(5), *second.GetId())
+	assert.Equal(t, "Parse Node", *second.GetDisplayName())
+
+	parseNode, err = NewJsonParseNode([]byte(`{"id": "5", "officeLocation": "Redmond", "displayName": "Parse Node"}`))
+	require.NoError(t, err)
+	value, err = parseNode.GetBestMatchingObjectValue(candidates...)
+	require.NoError(t, err)
+	entity, ok := value.(*internal.TestEntity)
+	require.True(t, ok, "%T", value)
+	assert.Equal(t, "Redmond", *entity.GetOfficeLocation())
+	assert.Equal(t, "Parse Node", *entity.GetAdditionalData()["displayName"].(*string))
+}
+
+func TestGetBestMatchingObjectValueInvokesHooksOnce(t *testing.T) {
+	parseNode, err := NewJsonParseNode([]byte(`{"id": 5}`))
+	require.NoError(t, err)
+	count := 0
+	require.NoError(t, parseNode.SetOnBeforeAssignFieldValues(func(absser.Parsable) error {
+		count++
+		return nil
+	}))
+	value, err := parseNode.GetBestMatchingObjectValue(internal.CreateTestEntityFromDiscriminator, internal.CreateSecondTestEntityFromDiscriminator)
+	require.NoError(t, err)
+	assert.IsType(t, &internal.SecondTestEntity{}, value)
+	assert.Equal(t, 1, count)
+}
+
+func TestGetBestMatchingObjectValueFailures(t *testing.T) {
+	parseNode, err := NewJsonParseNode([]byte(`{"value": [{"unknown": true}, "text"]}`))
+	require.NoError(t, err)
+	collection, err := parseNode.GetNodeAtPointer("/value")
+	require.NoError(t, err)
+
+	first, err := collection.GetNodeAtPointer("/0")
+	require.NoError(t, err)
+	_, err = first.GetBestMatchingObjectValue(internal.CreateTestEntityFromDiscriminator, internal.CreateSecondTestEntityFromDiscriminator)
+	var ambiguous *AmbiguousMatchError
+	require.True(t, errors.As(err, &ambiguous), "%v", err)
+	assert.Equal(t, "/value/0", ambiguous.Pointer)
+	assert.Equal(t, []int{0, 1}, ambiguous.Candidates)
+	assert.Equal(t, []string{"*internal.TestEntity", "*internal.SecondTestEntity"}, ambiguous.Types)
+
+	second, err := collection.GetNodeAtPointer("/1")
+	require.NoError(t, err)
+	_, err = second.GetBestMatchingObjectValue(internal.CreateTestEntityFromDiscriminator)
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr), "%v", err)
+	assert.Equal(t, "object", parseErr.Expected)
+
+	_, err = first.GetBestMatchingObjectValue()
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package jsonserialization
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WithStructuralValidation makes writers check that the writes produce a single JSON value: properties
+// must be written in objects, values in objects must have a key, a property must have a value and the
+// root holds one value. The first write breaking these rules fails with a *SerializationError, as does
+// every write that follows it, and nothing more is written. Content made of properties written outside of
+// any object, which writers accept otherwise, is rejected.
+func WithStructuralValidation() JsonSerializationWriterOption {
+	return serializationWriterOptionFunc(func(options *serializationWriterOptions) {
+		options.structuralValidation = true
+	})
+}
+
+// SerializationError describes a write that would make the content invalid JSON.
+// Use errors.As to retrieve it from the errors returned by JsonSerializationWriter.
+type SerializationError struct {
+	// Path is the RFC 6901 JSON Pointer of the value being written, built from the properties and elements
+	// written so far.
+	Path string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the message of the underlying error along with the path of the value.
+func (e *SerializationError) Error() string {
+	return fmt.Sprintf("%v (path %q)", e.Err, e.Path)
+}
+
+// Unwrap returns the underlying error.
+func (e *SerializationError) Unwrap() error {
+	return e.Err
+}
+
+// validateProperty checks that a property named key can be written.
+func (w *JsonSerializationWriter) validateProperty(key string) error {
+	state := w.containers[len(w.containers)-1]
+	switch {
+	case w.afterName:
+		return w.newSerializationError("", fmt.Errorf("the property %q has no value", state.name))
+	case state.array:
+		return w.newSerializationError(strconv.Itoa(state.count), fmt.Errorf("the property %q is written in an array, where values have no key", key))
+	case len(w.containers) == 1:
+		return w.newSerializationError(escapePointerToken(key), fmt.Errorf("the property %q is written outside of an object", key))
+	}
+	return nil
+}
+
+// validateValue checks that a value without a key can be written.
+func (w *JsonSerializationWriter) validateValue() error {
+	state := w.containers[len(w.containers)-1]
+	switch {
+	case state.array:
+		return nil
+	case len(w.containers) == 1:
+		if state.count != 0 || state.member {
+			return w.newSerializationError("", errors.New("the content already holds a root value"))
+		}
+		return nil
+	default:
+		return w.newSerializationError("", errors.New("a value is written in an object without a key"))
+	}
+}
+
+// validateContainerEnd checks that the object or array being written can end.
+func (w *JsonSerializationWriter) validateContainerEnd() error {
+	if w.afterName {
+		return w.newSerializationError("", fmt.Errorf("the property %q has no value", w.containers[len(w.containers)-1].name))
+	}
+	return nil
+}
+
+// newSerializationError returns the error of a write at the current path, followed by the next reference
+// token unless it is empty.
+func (w *JsonSerializationWriter) newSerializationError(next string, err error) *SerializationError {
+	var path strings.Builder
+	for i, state := range w.containers {
+		last := i == len(w.containers)-1
+		switch {
+		case state.array && !last:
+			path.WriteByte('/')
+			path.WriteString(strconv.Itoa(state.count - 1))
+		case !state.array && state.member && (!last || w.afterName):
+			path.WriteByte('/')
+			path.WriteString(escapePointerToken(state.name))
+		}
+	}
+	if next != "" {
+		path.WriteByte('/')
+		path.WriteString(next)
+	}
+	return &SerializationError{Path: path.String(), Err: err}
+}
@@ -0,0 +1,158 @@
+package jsonserialization
+
+import (
+	"errors"
+	"testing"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serializerFunc is a Parsable writing its properties with a function.
+type serializerFunc func(writer absser.SerializationWriter) error
+
+func (f serializerFunc) Serialize(writer absser.SerializationWriter) error {
+	return f(writer)
+}
+
+func (f serializerFunc) GetFieldDeserializers() map[string]func(absser.ParseNode) error {
+	return nil
+}
+
+func TestStructuralValidationAcceptsValidContent(t *testing.T) {
+	serializer := NewJsonSerializationWriter(WithStructuralValidation())
+	id := "1"
+	entity := internal.NewTestEntity()
+	entity.SetId(&id)
+	require.NoError(t, serializer.WriteObjectValue("", serializerFunc(func(writer absser.SerializationWriter) error {
+		if err := writer.WriteObjectValue("entity", entity); err != nil {
+			return err
+		}
+		if err := writer.WriteCollectionOfObjectValues("entities", []absser.Parsable{entity, nil}); err != nil {
+			return err
+		}
+		if err := writer.WriteObjectValue("untyped", absser.NewUntypedArray([]absser.UntypedNodeable{absser.NewUntypedObject(map[string]absser.UntypedNodeable{"a": absser.NewUntypedNull()})})); err != nil {
+			return err
+		}
+		return writer.WriteAdditionalData(map[string]interface{}{"count": int32(1)})
+	})))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `{"entity":{"id":"1"},"entities":[{"id":"1"},null],"untyped":[{"a":null}],"count":1}`, string(result))
+
+	serializer = NewJsonSerializationWriter(WithStructuralValidation())
+	require.NoError(t, serializer.WriteCollectionOfStringValues("", []string{"a"}))
+	result, err = serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `["a"]`, string(result))
+}
+
+func TestStructuralValidationRejectsInvalidWrites(t *testing.T) {
+	value := "value"
+	tests := []struct {
+		Name    string
+		Write   func(writer *JsonSerializationWriter) error
+		Path    string
+		Message string
+	}{
+		{
+			Name: "property at the root",
+			Write: func(writer *JsonSerializationWriter) error {
+				return writer.WriteStringValue("key", &value)
+			},
+			Path:    "/key",
+			Message: `the property "key" is written outside of an object (path "/key")`,
+		},
+		{
+			Name: "several root values",
+			Write: func(writer *JsonSerializationWriter) error {
+				if err := writer.WriteObjectValue("", internal.NewTestEntity()); err != nil {
+					return err
+				}
+				return writer.WriteStringValue("", &value)
+			},
+			Path:    "",
+			Message: `the content already holds a root value (path "")`,
+		},
+		{
+			Name: "value without a key in an object",
+			Write: func(writer *JsonSerializationWriter) error {
+				return writer.WriteObjectValue("", serializerFunc(func(writer absser.SerializationWriter) error {
+					return writer.WriteObjectValue("nested", serializerFunc(func(writer absser.SerializationWriter) error {
+						return writer.WriteStringValue("", &value)
+					}))
+				}))
+			},
+			Path:    "/nested",
+			Message: `a value is written in an object without a key (path "/nested")`,
+		},
+		{
+			Name: "property in an array",
+			Write: func(writer *JsonSerializationWriter) error {
+				return writer.WriteObjectValue("", serializerFunc(func(writer absser.SerializationWriter) error {
+					return writer.WriteCollectionOfObjectValues("a~b", []absser.Parsable{
+						internal.NewTestEntity(),
+						composedSerializerFunc(func(writer absser.SerializationWriter) error {
+							return writer.WriteStringValue("key", &value)
+						}),
+					})
+				}))
+			},
+			Path:    "/a~0b/1",
+			Message: `the property "key" is written in an array, where values have no key (path "/a~0b/1")`,
+		},
+		{
+			Name: "property without a value",
+			Write: func(writer *JsonSerializationWriter) error {
+				return writer.WriteObjectValue("", serializerFunc(func(writer absser.SerializationWriter) error {
+					return writer.WriteObjectValue("empty", composedSerializerFunc(func(absser.SerializationWriter) error {
+						return nil
+					}))
+				}))
+			},
+			Path:    "/empty",
+			Message: `the property "empty" has no value (path "/empty")`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			serializer := NewJsonSerializationWriter(WithStructuralValidation())
+			err := test.Write(serializer)
+			var serializationErr *SerializationError
+			require.True(t, errors.As(err, &serializationErr), "%v", err)
+			assert.Equal(t, test.Path, serializationErr.Path)
+			assert.EqualError(t, err, test.Message)
+
+			// every write that follows fails
+			assert.Equal(t, err, serializer.WriteNullValue(""))
+			assert.Equal(t, err, serializer.WriteStringValue("other", &value))
+		})
+	}
+}
+
+// composedSerializerFunc is a composed type wrapper writing its value with a function.
+type composedSerializerFunc func(writer absser.SerializationWriter) error
+
+func (f composedSerializerFunc) Serialize(writer absser.SerializationWriter) error {
+	return f(writer)
+}
+
+func (f composedSerializerFunc) GetFieldDeserializers() map[string]func(absser.ParseNode) error {
+	return nil
+}
+
+func (f composedSerializerFunc) GetIsComposedType() bool {
+	return true
+}
+
+func TestStructuralValidationIsOptional(t *testing.T) {
+	serializer := NewJsonSerializationWriter()
+	value := "value"
+	require.NoError(t, serializer.WriteStringValue("key", &value))
+	require.NoError(t, serializer.WriteStringValue("other", &value))
+	result, err := serializer.GetSerializedContent()
+	require.NoError(t, err)
+	assert.Equal(t, `"key":"value","other":"value"`, string(result))
+}
@@ -0,0 +1,103 @@
+package jsonserialization
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	absser "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// OrderedUntypedObject is an UntypedObject that remembers the order of its members. Parse nodes
+// produce it in round-trip mode and the serialization writer writes the members back in that order.
+type OrderedUntypedObject struct {
+	*absser.UntypedObject
+	keys []string
+}
+
+// NewOrderedUntypedObject creates a new OrderedUntypedObject. Members missing from keys are written
+// after the listed ones, sorted by key.
+func NewOrderedUntypedObject(keys []string, properties map[string]absser.UntypedNodeable) *OrderedUntypedObject {
+	return &OrderedUntypedObject{
+		UntypedObject: absser.NewUntypedObject(properties),
+		keys:          keys,
+	}
+}
+
+// GetKeys returns the keys of the members in order.
+func (o *OrderedUntypedObject) GetKeys() []string {
+	return orderedKeys(o.keys, o.GetValue())
+}
+
+// orderedKeys returns the keys of the members listed in order that are present, followed by the other
+// keys sorted so the output does not depend on map iteration.
+func orderedKeys[V any](order []string, members map[string]V) []string {
+	result := make([]string, 0, len(members))
+	seen := make(map[string]bool, len(members))
+	for _, key := range order {
+		if _, ok := members[key]; ok && !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	if len(result) == len(members) {
+		return result
+	}
+	rest := make([]string, 0, len(members)-len(result))
+	for key := range members {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(result, rest...)
+}
+
+// additionalDataOrders holds the order of the members read into additional data maps in round-trip
+// mode, keyed by the address of the map, so the unknown members of any AdditionalDataHolder are written
+// back in the order of the payload.
+var additionalDataOrders sync.Map
+
+// memberOrder is the order of the members read into an additional data map. It is forgotten once none
+// of the values read remain reachable, the map being unreachable or all of them having been replaced.
+type memberOrder struct {
+	keys []string
+	live atomic.Int32
+}
+
+// recordAdditionalDataOrder records the order of the keys of the additional data, whose values were
+// created while reading them.
+func recordAdditionalDataOrder(additionalData map[string]interface{}, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	address := reflect.ValueOf(additionalData).Pointer()
+	order := &memberOrder{keys: keys}
+	additionalDataOrders.Store(address, order)
+	for _, key := range keys {
+		value := additionalData[key]
+		if isNil(value) {
+			continue
+		}
+		order.live.Add(1)
+		runtime.SetFinalizer(value, func(interface{}) {
+			if order.live.Add(-1) == 0 {
+				additionalDataOrders.CompareAndDelete(address, order)
+			}
+		})
+	}
+	if order.live.Load() == 0 {
+		additionalDataOrders.CompareAndDelete(address, order)
+	}
+}
+
+// additionalDataOrder returns the keys of the additional data in the order they were read, or nil
+// when the map was not filled in round-trip mode.
+func additionalDataOrder(additionalData map[string]interface{}) []string {
+	if order, ok := additionalDataOrders.Load(reflect.ValueOf(additionalData).Pointer()); ok {
+		return order.(*memberOrder).keys
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package jsonserialization
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JsonKind is the kind of a JSON value as defined by RFC 8259.
+type JsonKind int
+
+const (
+	// UnknownJsonKind is used for values that were not read from a JSON payload.
+	UnknownJsonKind JsonKind = iota
+	NullJsonKind
+	BooleanJsonKind
+	NumberJsonKind
+	StringJsonKind
+	ArrayJsonKind
+	ObjectJsonKind
+)
+
+// String returns the name of the JSON kind.
+func (k JsonKind) String() string {
+	switch k {
+	case NullJsonKind:
+		return "null"
+	case BooleanJsonKind:
+		return "boolean"
+	case NumberJsonKind:
+		return "number"
+	case StringJsonKind:
+		return "string"
+	case ArrayJsonKind:
+		return "array"
+	case ObjectJsonKind:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseError describes a value of a JSON payload that could not be read.
+// Use errors.As to retrieve it from the errors returned by JsonParseNode.
+type ParseError struct {
+	// Pointer is the RFC 6901 JSON Pointer of the value, the empty string being the root.
+	Pointer string
+	// Offset is the byte offset of the value in the payload, -1 when the position is unknown.
+	Offset int64
+	// Line is the 1-based line of the value in the payload, 0 when the position is unknown.
+	Line int
+	// Column is the 1-based column of the value, counted in bytes, 0 when the position is unknown.
+	Column int
+	// Expected is the type the value was read as, empty when no particular type was expected.
+	Expected string
+	// Actual is the kind of JSON value found in the payload.
+	Actual JsonKind
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the message of the underlying error along with the location of the value.
+func (e *ParseError) Error() string {
+	message := "invalid value"
+	if e.Err != nil {
+		message = e.Err.Error()
+	}
+	if e.Offset < 0 {
+		return fmt.Sprintf("%s (pointer %q)", message, e.Pointer)
+	}
+	return fmt.Sprintf("%s (pointer %q, line %d, column %d, offset %d)", message, e.Pointer, e.Line, e.Column, e.Offset)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError returns a ParseError for the value of the node. Errors that already are a ParseError
+// were raised for a more specific value and are returned unchanged.
+func (n *JsonParseNode) newParseError(expected string, err error) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return err
+	}
+	if n == nil {
+		return &ParseError{Offset: -1, Expected: expected, Actual: NullJsonKind, Err: err}
+	}
+	return newParseErrorAt(n.pointer(), n.source, n.valueOffset(), n.value, expected, err)
+}
+
+// newParseErrorAt returns a ParseError for a value located at the given pointer and offset.
+func newParseErrorAt(pointer string, source *jsonSource, offset int64, value interface{}, expected string, err error) *ParseError {
+	result := &ParseError{
+		Pointer:  pointer,
+		Offset:   -1,
+		Expected: expected,
+		Actual:   jsonKindOf(value),
+		Err:      err,
+	}
+	if source != nil && offset >= 0 {
+		result.Offset = offset
+		result.Line, result.Column = source.position(offset)
+	}
+	return result
+}
+
+// jsonKindOf returns the JSON kind of a value stored in a parse tree.
+func jsonKindOf(value interface{}) JsonKind {
+	switch value.(type) {
+	case nil:
+		return NullJsonKind
+	case *string:
+		return StringJsonKind
+	case *bool:
+		return BooleanJsonKind
+	case *json.Number:
+		return NumberJsonKind
+	case map[string]interface{}:
+		return ObjectJsonKind
+	case []interface{}:
+		return ArrayJsonKind
+	case *JsonParseNode:
+		return jsonKindOf(value.(*JsonParseNode).value)
+	}
+	if isNil(value) {
+		return NullJsonKind
+	}
+	if isNumericType(reflect.Indirect(reflect.ValueOf(value)).Type()) {
+		return NumberJsonKind
+	}
+	return UnknownJsonKind
+}
+
+// pointerTokenReplacer escapes reference tokens as required by RFC 6901.
+var pointerTokenReplacer = strings.NewReplacer("~", "~0", "/", "~1")
+
+// childPointer returns the JSON Pointer of the member of an object.
+func childPointer(parent string, key string) string {
+	return parent + "/" + pointerTokenReplacer.Replace(key)
+}
+
+// elementPointer returns the JSON Pointer of the element of an array.
+func elementPointer(parent string, index int) string {
+	return parent + "/" + strconv.Itoa(index)
+}
+
+// AggregateParseError gathers the failures met while reading a payload in collect-all-errors mode,
+// see WithCollectAllErrors. Each failure is a *ParseError locating the faulty value.
+type AggregateParseError struct {
+	Errors []*ParseError
+}
+
+// Error returns the messages of all the failures.
+func (e *AggregateParseError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred while parsing: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the failures so errors.Is and errors.As inspect each of them.
+func (e *AggregateParseError) Unwrap() []error {
+	result := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		result[i] = err
+	}
+	return result
+}
+
+// errorCollector gathers the failures of a collect-all-errors read. It is shared by the nodes taking part
+// in the read and owned by the node the read started from.
+type errorCollector struct {
+	errors []*ParseError
+	done   bool
+}
+
+// add records a failure, errors that are not a ParseError are attributed to the node.
+func (c *errorCollector) add(n *JsonParseNode, err error) {
+	var aggregate *AggregateParseError
+	if errors.As(err, &aggregate) {
+		c.errors = append(c.errors, aggregate.Errors...)
+		return
+	}
+	var parseErr *ParseError
+	if !errors.As(n.newParseError("", err), &parseErr) {
+		return
+	}
+	c.errors = append(c.errors, parseErr)
+}
+
+// finish ends the read and returns the failures met, nil when there was none.
+func (c *errorCollector) finish() error {
+	c.done = true
+	if len(c.errors) == 0 {
+		return nil
+	}
+	// fields are visited in no particular order, report the failures in document order
+	sort.SliceStable(c.errors, func(i, j int) bool {
+		if c.errors[i].Offset != c.errors[j].Offset {
+			return c.errors[i].Offset < c.errors[j].Offset
+		}
+		return c.errors[i].Pointer < c.errors[j].Pointer
+	})
+	return &AggregateParseError{Errors: c.errors}
+}
+
+// startCollecting returns the collector the node reports its failures to in collect-all-errors mode and
+// whether the node owns it, nil when the mode is disabled.
+func (n *JsonParseNode) startCollecting() (*errorCollector, bool) {
+	if !n.getOptions().collectAllErrors {
+		return nil, false
+	}
+	if n.collector != nil && !n.collector.done {
+		return n.collector, false
+	}
+	n.collector = &errorCollector{}
+	return n.collector, true
+}
@@ -0,0 +1,94 @@
+package jsonserialization
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/kiota-serialization-json-go/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorLocatesFaultyProperty(t *testing.T) {
+	source := "[\n  {\"id\": \"1\"},\n  {\"id\": \"2\", \"birthDay\": \"not a date\"}\n]"
+
+	for _, fromReader := range []bool{false, true} {
+		var parseNode *JsonParseNode
+		var err error
+		if fromReader {
+			parseNode, err = NewJsonParseNodeFromReader(strings.NewReader(source))
+		} else {
+			parseNode, err = NewJsonParseNode([]byte(source))
+		}
+		require.NoError(t, err)
+
+		_, err = parseNode.GetCollectionOfObjectValues(internal.CreateTestEntityFromDiscriminator)
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, "/1/birthDay", parseErr.Pointer)
+		assert.Equal(t, 3, parseErr.Line)
+		assert.Equal(t, 27, parseErr.Column)
+		assert.Equal(t, int64(strings.Index(source, `"not a date"`)), parseErr.Offset)
+		assert.Equal(t, "DateOnly", parseErr.Expected)
+		assert.Equal(t, StringJsonKind, parseErr.Actual)
+		assert.Contains(t, err.Error(), `pointer "/1/birthDay", line 3, column 27`)
+	}
+}
+
+func TestParseErrorFromPrimitiveGetters(t *testing.T) {
+	source := `{"a/b": {"values": [1, 2, "three"]}, "count": 3.5}`
+	parseNode, err := NewJsonParseNode([]byte(source))
+	require.NoError(t, err)
+
+	count, err := parseNode.GetChildNode("count")
+	require.NoError(t, err)
+	_, err = count.GetInt32Value()
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, "/count", parseErr.Pointer)
+	assert.Equal(t, "int32", parseErr.Expected)
+	assert.Equal(t, NumberJsonKind, parseErr.Actual)
+	assert.Equal(t, int64(strings.Index(source, "3.5")), parseErr.Offset)
+	assert.EqualError(t, parseErr.Err, "value '3.5' is not compatible with type int32")
+
+	parent, err := parseNode.GetChildNode("a/b")
+	require.NoError(t, err)
+	values, err := parent.GetChildNode("values")
+	require.NoError(t, err)
+	_, err = values.GetCollectionOfPrimitiveValues("int64")
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, "/a~1b/values/2", parseErr.Pointer)
+	assert.Equal(t, "int64", parseErr.Expected)
+	assert.Equal(t, StringJsonKind, parseErr.Actual)
+	assert.Equal(t, 1, parseErr.Line)
+	assert.Equal(t, strings.Index(source, `"three"`)+1, parseErr.Column)
+
+	// faulty calls are not reported as faulty payloads
+	_, err = values.GetCollectionOfPrimitiveValues("unknown")
+	assert.False(t, errors.As(err, &parseErr))
+}
+
+func TestParseErrorFromSyntaxError(t *testing.T) {
+	_, err := NewJsonParseNodeFromReader(strings.NewReader("{\n  \"a\": [1, 2}\n}"))
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, "/a", parseErr.Pointer)
+	assert.Equal(t, 2, parseErr.Line)
+	assert.Equal(t, 13, parseErr.Column)
+}
+
+func TestParseErrorWithoutSource(t *testing.T) {
+	node := &JsonParseNode{value: "not a pointer"}
+	_, err := node.GetBoolValue()
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, int64(-1), parseErr.Offset)
+	assert.Equal(t, 0, parseErr.Line)
+	assert.Equal(t, `type 'string' is not compatible with type bool (pointer "")`, err.Error())
+}
+
+func TestJsonPointerEscaping(t *testing.T) {
+	assert.Equal(t, "/a~0b~1c", childPointer("", "a~b/c"))
+	assert.Equal(t, "/a/3", elementPointer("/a", 3))
+}
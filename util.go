@@ -1,8 +1,10 @@
 package jsonserialization
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -92,19 +94,27 @@ func as[T any](in interface{}, out T) error {
 
 	outType := nestedOutVal.Type()
 
+	// JSON numbers are kept as their lexeme, parse them straight into the target type so
+	// integers beyond the precision of a float64 are not truncated on the way.
+	if number, ok := in.(json.Number); ok {
+		parsed, ok := parseNumberAs(number.String(), outType)
+		if !ok {
+			return fmt.Errorf("value '%v' is not compatible with type %T", in, nestedOutVal.Interface())
+		}
+		outVal.Elem().Set(parsed)
+		return nil
+	}
+
 	// Numbers encoded as JSON strings (e.g. "1") arrive here as a plain string after the
 	// pointer dereference above. Parse the string into a numeric value and run it through the
 	// same range/decimal compatibility checks used for native numbers, so callers get a value
 	// for valid strings ("1" -> int32(1), "1.5" -> float64(1.5)) and a clear error otherwise.
 	if s, ok := in.(string); ok && isNumericType(outType) {
-		parsed, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
-		if err != nil {
-			return fmt.Errorf("value '%v' is not compatible with type %T", in, nestedOutVal.Interface())
-		}
-		if !isCompatibleInt(parsed, outType) {
+		parsed, ok := parseNumberAs(strings.TrimSpace(s), outType)
+		if !ok {
 			return fmt.Errorf("value '%v' is not compatible with type %T", in, nestedOutVal.Interface())
 		}
-		outVal.Elem().Set(reflect.ValueOf(parsed).Convert(outType))
+		outVal.Elem().Set(parsed)
 		return nil
 	}
 
@@ -163,3 +173,198 @@ func isCompatibleInt(in interface{}, tp reflect.Type) bool {
 func hasDecimalPlace(value float64) bool {
 	return value != float64(int64(value))
 }
+
+// parseNumberAs parses the textual representation of a number into a value of type tp.
+// Integers are parsed exactly, anything else goes through the same range and decimal checks
+// as native numbers. It returns false when the text is not compatible with the type.
+func parseNumberAs(s string, tp reflect.Type) (reflect.Value, bool) {
+	out := reflect.New(tp).Elem()
+	switch tp.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			if out.OverflowInt(i) {
+				return out, false
+			}
+			out.SetInt(i)
+			return out, true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			if out.OverflowUint(u) {
+				return out, false
+			}
+			out.SetUint(u)
+			return out, true
+		}
+	case reflect.Float32, reflect.Float64:
+	default:
+		return out, false
+	}
+
+	isInteger := tp.Kind() != reflect.Float32 && tp.Kind() != reflect.Float64
+	if isInteger && !strings.ContainsAny(s, ".eE") {
+		// an integer that did not parse above is either malformed or out of range
+		return out, false
+	}
+	parsed, err := strconv.ParseFloat(s, 64)
+	if err != nil || !isCompatibleInt(parsed, tp) {
+		return out, false
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, which passes the range check but does not convert
+	if isInteger && math.Abs(parsed) >= 1<<63 {
+		return out, false
+	}
+	out.Set(reflect.ValueOf(parsed).Convert(tp))
+	return out, true
+}
+
+// numberText returns the textual representation of a numeric value, or of a number encoded as a string.
+func numberText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case *json.Number:
+		return v.String(), true
+	case *string:
+		return strings.TrimSpace(*v), true
+	case *float64:
+		return strconv.FormatFloat(*v, 'g', -1, 64), true
+	case *float32:
+		return strconv.FormatFloat(float64(*v), 'g', -1, 32), true
+	}
+	if isNil(value) {
+		return "", false
+	}
+	valValue := reflect.ValueOf(value)
+	for valValue.Kind() == reflect.Ptr {
+		valValue = valValue.Elem()
+	}
+	if !isNumericType(valValue.Type()) {
+		return "", false
+	}
+	return fmt.Sprint(valValue.Interface()), true
+}
+
+// parseBigFloat parses a decimal number with enough precision to keep all of its digits.
+func parseBigFloat(s string) (*big.Float, error) {
+	// 4 bits per decimal digit is more than the log2(10) needed to represent each of them
+	prec := uint(len(s)) * 4
+	if prec < 64 {
+		prec = 64
+	}
+	f, _, err := big.ParseFloat(s, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, err
+	}
+	if f.IsInf() {
+		return nil, fmt.Errorf("value '%s' is not a finite number", s)
+	}
+	return f, nil
+}
+
+// maxIntegralDigits is the largest number of digits of the integers read from a number written with a
+// fraction or an exponent, so a short payload such as 1e100000000 cannot build a huge integer.
+const maxIntegralDigits = 20000
+
+// parseIntegralNumber returns the integer written by a number with a fraction or an exponent, e.g. 1.0 or
+// 1e3, exactly.
+func parseIntegralNumber(s string) (*big.Int, error) {
+	if !isValidNumber(s) {
+		return nil, fmt.Errorf("value '%s' is not compatible with type big.Int", s)
+	}
+	number, ok := parseDecimalNumber(s)
+	if !ok {
+		return nil, fmt.Errorf("value '%s' has more than %d digits", s, maxIntegralDigits)
+	}
+	if number.digits == "" {
+		return new(big.Int), nil
+	}
+	if number.exponent < 0 {
+		return nil, fmt.Errorf("value '%s' is not compatible with type big.Int", s)
+	}
+	if int64(len(number.digits))+number.exponent > maxIntegralDigits {
+		return nil, fmt.Errorf("value '%s' has more than %d digits", s, maxIntegralDigits)
+	}
+	value, _ := new(big.Int).SetString(number.digits, 10)
+	value.Mul(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(number.exponent), nil))
+	if number.negative {
+		value.Neg(value)
+	}
+	return value, nil
+}
+
+// decimalNumber is the exact value of a number, digits * 10^exponent. Digits have no leading or trailing
+// zeros and are empty for zero, so equal numbers have equal decimalNumbers.
+type decimalNumber struct {
+	negative bool
+	digits   string
+	exponent int64
+}
+
+// parseDecimalNumber returns the exact value of a JSON number without computing it, so large exponents
+// cost nothing. It returns false when the number is invalid or its exponent does not fit 32 bits.
+func parseDecimalNumber(s string) (decimalNumber, bool) {
+	if !isValidNumber(s) {
+		return decimalNumber{}, false
+	}
+	mantissa, exponentText, hasExponent := strings.Cut(strings.ToLower(s), "e")
+	var exponent int64
+	if hasExponent {
+		var err error
+		if exponent, err = strconv.ParseInt(exponentText, 10, 32); err != nil {
+			return decimalNumber{}, false
+		}
+	}
+	negative := strings.HasPrefix(mantissa, "-")
+	integer, fraction, _ := strings.Cut(strings.TrimPrefix(mantissa, "-"), ".")
+	digits := strings.TrimLeft(integer+fraction, "0")
+	trimmed := strings.TrimRight(digits, "0")
+	if trimmed == "" {
+		return decimalNumber{}, true
+	}
+	return decimalNumber{
+		negative: negative,
+		digits:   trimmed,
+		exponent: exponent - int64(len(fraction)) + int64(len(digits)-len(trimmed)),
+	}, true
+}
+
+// isValidNumber reports whether s follows the JSON grammar for numbers.
+func isValidNumber(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(s) && s[i] == '0':
+		i++
+	case i < len(s) && s[i] >= '1' && s[i] <= '9':
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	default:
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	return i == len(s)
+}
@@ -1,6 +1,7 @@
 package jsonserialization
 
 import (
+	"encoding/json"
 	"errors"
 	"reflect"
 	"testing"
@@ -94,6 +95,33 @@ func TestAs(t *testing.T) {
 			Expected: int8(0),
 			Error:    errors.New("value 'I am a string' is not compatible with type int8"),
 		},
+		{
+			Title: "Exact Number",
+			InputVal: []interface{}{
+				json.Number("9007199254740993"),
+				int64(0),
+			},
+			Expected: int64(9007199254740993),
+			Error:    nil,
+		},
+		{
+			Title: "Number Overflow",
+			InputVal: []interface{}{
+				json.Number("9223372036854775808"),
+				int64(0),
+			},
+			Expected: int64(0),
+			Error:    errors.New("value '9223372036854775808' is not compatible with type int64"),
+		},
+		{
+			Title: "Number To String",
+			InputVal: []interface{}{
+				json.Number("1"),
+				"",
+			},
+			Expected: "",
+			Error:    errors.New("value '1' is not compatible with type string"),
+		},
 		{
 			Title: "Untyped Nil - In",
 			InputVal: []interface{}{
@@ -315,3 +343,12 @@ func TestHasDecimal(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidNumber(t *testing.T) {
+	for _, valid := range []string{"0", "-0", "1", "-12", "1.5", "0.25", "1e3", "1E+3", "-1.5e-3"} {
+		assert.True(t, isValidNumber(valid), valid)
+	}
+	for _, invalid := range []string{"", "-", "01", "1.", ".5", "1e", "1e+", "+1", "0x10", "NaN", "1.5.2"} {
+		assert.False(t, isValidNumber(invalid), invalid)
+	}
+}